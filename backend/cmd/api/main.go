@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -30,9 +32,20 @@ func main() {
 	// Auto-migrate models
 	if err := db.AutoMigrate(
 		&models.User{},
+		&models.Schedule{},
 		&models.SchedulingWindow{},
+		&models.SchedulingOverride{},
+		&models.HolidayCalendar{},
+		&models.Holiday{},
 		&models.SchedulingLink{},
+		&models.Client{},
 		&models.Meeting{},
+		&models.MeetingSeries{},
+		&models.MeetingNote{},
+		&models.MeetingStatusChange{},
+		&models.SlotHold{},
+		&models.WaitlistEntry{},
+		&models.IdempotencyKey{},
 		&models.GoogleAccount{},
 		&models.HubSpotAccount{},
 	); err != nil {
@@ -42,10 +55,39 @@ func main() {
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(db)
 	emailService := services.NewEmailService(db)
-	schedulingHandler := handlers.NewSchedulingHandler(db, emailService)
+	calendarService := services.NewCalendarService(db)
+	schedulingHandler := handlers.NewSchedulingHandler(db, emailService, calendarService)
 	hubspotHandler := handlers.NewHubSpotHandler(db)
 	googleHandler := handlers.NewGoogleHandler(db)
 	calendarHandler := handlers.NewCalendarHandler(db)
+
+	// Link meetings booked before clients were kept to their clients
+	if err := schedulingHandler.LinkMeetingsToClients(); err != nil {
+		log.Printf("Failed to link meetings to clients: %v", err)
+	}
+
+	// Expire pending booking requests the advisor didn't act on in time, and forget the results of
+	// idempotent requests once they're too old to be retried
+	go func() {
+		for range time.Tick(time.Minute) {
+			if err := schedulingHandler.ExpirePendingMeetings(); err != nil {
+				log.Printf("Failed to expire pending meetings: %v", err)
+			}
+			if err := schedulingHandler.PruneIdempotencyKeys(); err != nil {
+				log.Printf("Failed to prune idempotency keys: %v", err)
+			}
+		}
+	}()
+
+	// Offer the slots of lapsed waitlist offers to the rest of their waitlists
+	go func() {
+		for range time.Tick(5 * time.Minute) {
+			if err := schedulingHandler.ProcessWaitlists(context.Background()); err != nil {
+				log.Printf("Failed to process waitlists: %v", err)
+			}
+		}
+	}()
+
 	// Setup router
 	router := gin.Default()
 
@@ -58,7 +100,20 @@ func main() {
 	})
 	router.GET("/scheduling/links/:id/public", schedulingHandler.GetPublicSchedulingLink)
 	router.GET("/scheduling/links/:id/slots/public", schedulingHandler.GetPublicAvailableSlots)
+	router.GET("/scheduling/links/:id/slots/public/range", schedulingHandler.GetPublicAvailableSlotsRange)
+	router.GET("/scheduling/links/:id/slots/public/first-available", schedulingHandler.GetPublicFirstAvailableSlots)
 	router.POST("/scheduling/links/:id/meetings/public", schedulingHandler.CreatePublicMeeting)
+	router.POST("/scheduling/links/:id/meetings/public/series", schedulingHandler.CreatePublicMeetingSeries)
+	router.POST("/scheduling/links/:id/holds/public", middleware.RateLimit(20, time.Minute), schedulingHandler.CreatePublicSlotHold)
+	router.DELETE("/scheduling/holds/public/:token", schedulingHandler.DeletePublicSlotHold)
+	router.POST("/scheduling/links/:id/waitlist/public", schedulingHandler.JoinPublicWaitlist)
+	router.DELETE("/scheduling/waitlist/public/:token", schedulingHandler.LeavePublicWaitlist)
+	router.GET("/scheduling/waitlist/claim/:token", schedulingHandler.GetPublicWaitlistClaim)
+	router.GET("/scheduling/meetings/manage/:token", schedulingHandler.GetInviteeMeeting)
+	router.GET("/scheduling/meetings/manage/:token/confirmation", schedulingHandler.GetInviteeMeetingConfirmation)
+	router.GET("/scheduling/meetings/manage/:token/ics", schedulingHandler.GetInviteeMeetingICS)
+	router.POST("/scheduling/meetings/manage/:token/cancel", schedulingHandler.CancelInviteeMeeting)
+	router.POST("/scheduling/meetings/manage/:token/reschedule", schedulingHandler.RescheduleInviteeMeeting)
 
 	// Protected routes
 	protected := router.Group("/api")
@@ -72,9 +127,54 @@ func main() {
 			scheduling.GET("/links/:id", schedulingHandler.GetSchedulingLink)
 			scheduling.GET("/links/:id/slots", schedulingHandler.GetAvailableSlots)
 			scheduling.GET("/links/:id/meetings", schedulingHandler.GetLinkMeetings)
+			scheduling.GET("/links/:id/waitlist", schedulingHandler.GetLinkWaitlist)
+			scheduling.PUT("/links/:id/schedule", schedulingHandler.UpdateLinkSchedule)
+			scheduling.GET("/links/:id/explain", schedulingHandler.ExplainSlot)
+			scheduling.POST("/meetings/:id/cancel", schedulingHandler.CancelMeeting)
+			scheduling.GET("/series/:id", schedulingHandler.GetMeetingSeries)
+			scheduling.POST("/series/:id/cancel", schedulingHandler.CancelMeetingSeries)
 			scheduling.POST("/windows", schedulingHandler.CreateSchedulingWindow)
 			scheduling.GET("/windows", schedulingHandler.GetSchedulingWindows)
 			scheduling.DELETE("/windows/:id", schedulingHandler.DeleteSchedulingWindow)
+			scheduling.POST("/overrides", schedulingHandler.CreateSchedulingOverride)
+			scheduling.GET("/overrides", schedulingHandler.GetSchedulingOverrides)
+			scheduling.GET("/overrides/:id", schedulingHandler.GetSchedulingOverride)
+			scheduling.PUT("/overrides/:id", schedulingHandler.UpdateSchedulingOverride)
+			scheduling.DELETE("/overrides/:id", schedulingHandler.DeleteSchedulingOverride)
+			scheduling.POST("/schedules", schedulingHandler.CreateSchedule)
+			scheduling.GET("/schedules", schedulingHandler.GetSchedules)
+			scheduling.GET("/schedules/:id", schedulingHandler.GetSchedule)
+			scheduling.PUT("/schedules/:id", schedulingHandler.UpdateSchedule)
+			scheduling.DELETE("/schedules/:id", schedulingHandler.DeleteSchedule)
+			scheduling.GET("/holidays/datasets", schedulingHandler.GetHolidayDatasets)
+			scheduling.POST("/holidays/bundled", schedulingHandler.ImportBundledHolidays)
+			scheduling.POST("/holidays/upload", schedulingHandler.UploadHolidayCalendar)
+			scheduling.GET("/holidays", schedulingHandler.GetHolidayCalendars)
+			scheduling.GET("/holidays/:id", schedulingHandler.GetHolidayCalendar)
+			scheduling.DELETE("/holidays/:id", schedulingHandler.DeleteHolidayCalendar)
+			scheduling.GET("/timezone", schedulingHandler.GetTimezone)
+			scheduling.PUT("/timezone", schedulingHandler.UpdateTimezone)
+		}
+
+		// Meeting routes
+		meetings := protected.Group("/meetings")
+		{
+			meetings.GET("", schedulingHandler.GetMeetings)
+			meetings.GET("/:id", schedulingHandler.GetMeeting)
+			meetings.POST("/:id/cancel", schedulingHandler.CancelMeeting)
+			meetings.POST("/:id/reschedule", schedulingHandler.RescheduleMeeting)
+			meetings.POST("/:id/no-show", schedulingHandler.MarkMeetingNoShow)
+			meetings.POST("/:id/complete", schedulingHandler.CompleteMeeting)
+			meetings.POST("/:id/accept", schedulingHandler.AcceptMeeting)
+			meetings.POST("/:id/decline", schedulingHandler.DeclineMeeting)
+			meetings.POST("/:id/notes", schedulingHandler.AddMeetingNote)
+		}
+
+		// Client routes
+		clients := protected.Group("/clients")
+		{
+			clients.GET("", schedulingHandler.GetClients)
+			clients.GET("/:id", schedulingHandler.GetClient)
 		}
 
 		// Google routes
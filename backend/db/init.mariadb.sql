@@ -21,9 +21,24 @@ CREATE TABLE users (
     calendar_ids JSON,
     last_login_at TIMESTAMP NULL DEFAULT NULL,
     is_active BOOLEAN DEFAULT TRUE,
+    timezone VARCHAR(64) DEFAULT 'UTC',
     UNIQUE KEY unique_email (email)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- Create schedules table
+CREATE TABLE schedules (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT fk_schedules_user
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- Create scheduling_windows table
 CREATE TABLE scheduling_windows (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
@@ -31,15 +46,90 @@ CREATE TABLE scheduling_windows (
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP NULL DEFAULT NULL,
     user_id BIGINT UNSIGNED NOT NULL,
+    schedule_id BIGINT UNSIGNED NULL DEFAULT NULL,
     start_hour TINYINT UNSIGNED NOT NULL,
+    start_minute TINYINT UNSIGNED NOT NULL DEFAULT 0,
     end_hour TINYINT UNSIGNED NOT NULL,
+    end_minute TINYINT UNSIGNED NOT NULL DEFAULT 0,
     weekday TINYINT UNSIGNED NOT NULL,
+    timezone VARCHAR(64) NULL DEFAULT NULL,
     is_active BOOLEAN DEFAULT TRUE,
     CONSTRAINT fk_scheduling_windows_user
         FOREIGN KEY (user_id) REFERENCES users(id)
         ON DELETE CASCADE,
+    CONSTRAINT fk_scheduling_windows_schedule
+        FOREIGN KEY (schedule_id) REFERENCES schedules(id)
+        ON DELETE CASCADE,
     CONSTRAINT valid_weekday CHECK (weekday <= 6),
-    CONSTRAINT valid_hours CHECK (start_hour < end_hour AND start_hour <= 23 AND end_hour <= 23)
+    CONSTRAINT valid_minutes CHECK (start_minute <= 59 AND end_minute <= 59),
+    CONSTRAINT valid_hours CHECK (start_hour * 60 + start_minute < end_hour * 60 + end_minute AND end_hour * 60 + end_minute <= 1440)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create scheduling_overrides table
+CREATE TABLE scheduling_overrides (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    schedule_id BIGINT UNSIGNED NULL DEFAULT NULL,
+    start_date VARCHAR(10) NOT NULL,
+    end_date VARCHAR(10) NOT NULL,
+    start_minute SMALLINT UNSIGNED NULL DEFAULT NULL,
+    end_minute SMALLINT UNSIGNED NULL DEFAULT NULL,
+    is_available BOOLEAN NOT NULL,
+    reason VARCHAR(255),
+    CONSTRAINT fk_scheduling_overrides_user
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_scheduling_overrides_schedule
+        FOREIGN KEY (schedule_id) REFERENCES schedules(id)
+        ON DELETE CASCADE,
+    CONSTRAINT valid_override_dates CHECK (start_date <= end_date),
+    CONSTRAINT valid_override_minutes CHECK (
+        (start_minute IS NULL AND end_minute IS NULL)
+        OR (start_minute < end_minute AND end_minute <= 1440)
+    )
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create holiday_calendars table
+CREATE TABLE holiday_calendars (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    schedule_id BIGINT UNSIGNED NULL DEFAULT NULL,
+    name VARCHAR(255) NOT NULL,
+    source VARCHAR(16) NOT NULL,
+    country VARCHAR(2),
+    CONSTRAINT fk_holiday_calendars_user
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_holiday_calendars_schedule
+        FOREIGN KEY (schedule_id) REFERENCES schedules(id)
+        ON DELETE CASCADE,
+    CONSTRAINT valid_holiday_source CHECK (source IN ('bundled', 'ics'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create holidays table
+CREATE TABLE holidays (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    holiday_calendar_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    start_date VARCHAR(10) NOT NULL,
+    end_date VARCHAR(10) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    CONSTRAINT fk_holidays_calendar
+        FOREIGN KEY (holiday_calendar_id) REFERENCES holiday_calendars(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_holidays_user
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT valid_holiday_dates CHECK (start_date <= end_date)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- Create scheduling_links table
@@ -49,19 +139,87 @@ CREATE TABLE scheduling_links (
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP NULL DEFAULT NULL,
     user_id BIGINT UNSIGNED NOT NULL,
+    schedule_id BIGINT UNSIGNED NULL DEFAULT NULL,
     title VARCHAR(255) NOT NULL,
     duration SMALLINT UNSIGNED NOT NULL,
+    seats SMALLINT UNSIGNED NOT NULL DEFAULT 1,
     max_uses INT UNSIGNED NULL DEFAULT NULL,
     expires_at TIMESTAMP NULL DEFAULT NULL,
+    horizon_mode VARCHAR(16) NOT NULL DEFAULT 'rolling',
     max_days_in_advance SMALLINT UNSIGNED NOT NULL,
+    start_date VARCHAR(10) NULL DEFAULT NULL,
+    end_date VARCHAR(10) NULL DEFAULT NULL,
+    buffer_before SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+    buffer_after SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+    min_notice INT UNSIGNED NOT NULL DEFAULT 0,
+    slot_interval SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+    top_of_hour_only BOOLEAN DEFAULT FALSE,
+    max_per_day INT UNSIGNED NULL DEFAULT NULL,
+    max_per_week INT UNSIGNED NULL DEFAULT NULL,
+    max_occurrences SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+    slot_ranking VARCHAR(16) NOT NULL DEFAULT 'none',
+    requires_approval BOOLEAN DEFAULT FALSE,
+    approval_hours SMALLINT UNSIGNED NOT NULL DEFAULT 24,
+    max_guests SMALLINT UNSIGNED NOT NULL DEFAULT 0,
     custom_questions JSON,
     is_active BOOLEAN DEFAULT TRUE,
     CONSTRAINT fk_scheduling_links_user
         FOREIGN KEY (user_id) REFERENCES users(id)
         ON DELETE CASCADE,
+    CONSTRAINT fk_scheduling_links_schedule
+        FOREIGN KEY (schedule_id) REFERENCES schedules(id)
+        ON DELETE SET NULL,
     CONSTRAINT positive_duration CHECK (duration > 0),
+    CONSTRAINT positive_seats CHECK (seats > 0),
     CONSTRAINT positive_max_uses CHECK (max_uses IS NULL OR max_uses > 0),
-    CONSTRAINT positive_max_days CHECK (max_days_in_advance > 0)
+    CONSTRAINT valid_slot_ranking CHECK (slot_ranking IN ('none', 'compact', 'compact_only')),
+    CONSTRAINT valid_horizon_mode CHECK (horizon_mode IN ('rolling', 'business_days', 'fixed')),
+    CONSTRAINT positive_max_days CHECK (max_days_in_advance > 0 OR horizon_mode = 'fixed'),
+    CONSTRAINT valid_fixed_range CHECK (horizon_mode <> 'fixed' OR (start_date IS NOT NULL AND end_date IS NOT NULL AND start_date <= end_date)),
+    CONSTRAINT positive_max_per_day CHECK (max_per_day IS NULL OR max_per_day > 0),
+    CONSTRAINT positive_max_per_week CHECK (max_per_week IS NULL OR max_per_week > 0),
+    CONSTRAINT positive_approval_hours CHECK (approval_hours > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create clients table
+CREATE TABLE clients (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NULL DEFAULT NULL,
+    phone VARCHAR(32) NULL DEFAULT NULL,
+    linkedin_url VARCHAR(255) NULL DEFAULT NULL,
+    hubspot_contact_id VARCHAR(255) NULL DEFAULT NULL,
+    linkedin_data JSON,
+    CONSTRAINT fk_clients_user
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT idx_clients_user_email UNIQUE (user_id, email)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create meeting_series table
+CREATE TABLE meeting_series (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    scheduling_link_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    client_email VARCHAR(255) NOT NULL,
+    frequency VARCHAR(16) NOT NULL,
+    `interval` SMALLINT UNSIGNED NOT NULL DEFAULT 1,
+    occurrences SMALLINT UNSIGNED NOT NULL,
+    CONSTRAINT fk_meeting_series_scheduling_link
+        FOREIGN KEY (scheduling_link_id) REFERENCES scheduling_links(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_meeting_series_user
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT valid_series_frequency CHECK (frequency IN ('weekly', 'monthly')),
+    CONSTRAINT positive_series_interval CHECK (`interval` > 0)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- Create meetings table
@@ -72,7 +230,11 @@ CREATE TABLE meetings (
     deleted_at TIMESTAMP NULL DEFAULT NULL,
     scheduling_link_id BIGINT UNSIGNED NOT NULL,
     user_id BIGINT UNSIGNED NOT NULL,
+    client_id BIGINT UNSIGNED NULL DEFAULT NULL,
     client_email VARCHAR(255) NOT NULL,
+    client_name VARCHAR(255) NULL DEFAULT NULL,
+    client_phone VARCHAR(32) NULL DEFAULT NULL,
+    guest_emails JSON,
     linkedin_url VARCHAR(255) NULL DEFAULT NULL,
     start_time TIMESTAMP NOT NULL,
     end_time TIMESTAMP NOT NULL,
@@ -80,13 +242,126 @@ CREATE TABLE meetings (
     hubspot_contact_id VARCHAR(255) NULL DEFAULT NULL,
     linkedin_data JSON,
     context_notes TEXT,
+    status VARCHAR(16) NOT NULL DEFAULT 'scheduled',
+    series_id BIGINT UNSIGNED NULL DEFAULT NULL,
+    cancelled_at TIMESTAMP NULL DEFAULT NULL,
+    cancel_reason TEXT,
+    rescheduled_at TIMESTAMP NULL DEFAULT NULL,
+    approval_deadline TIMESTAMP NULL DEFAULT NULL,
+    decline_reason TEXT,
     CONSTRAINT fk_meetings_scheduling_link
         FOREIGN KEY (scheduling_link_id) REFERENCES scheduling_links(id)
         ON DELETE CASCADE,
     CONSTRAINT fk_meetings_user
         FOREIGN KEY (user_id) REFERENCES users(id)
         ON DELETE CASCADE,
-    CONSTRAINT valid_time_range CHECK (start_time < end_time)
+    CONSTRAINT fk_meetings_series
+        FOREIGN KEY (series_id) REFERENCES meeting_series(id)
+        ON DELETE SET NULL,
+    CONSTRAINT fk_meetings_client
+        FOREIGN KEY (client_id) REFERENCES clients(id)
+        ON DELETE SET NULL,
+    CONSTRAINT valid_time_range CHECK (start_time < end_time),
+    CONSTRAINT valid_meeting_status CHECK (status IN ('pending', 'scheduled', 'completed', 'no_show', 'cancelled', 'declined', 'expired'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create meeting_status_changes table
+CREATE TABLE meeting_status_changes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    meeting_id BIGINT UNSIGNED NOT NULL,
+    from_status VARCHAR(16),
+    to_status VARCHAR(16) NOT NULL,
+    event VARCHAR(16) NOT NULL,
+    actor VARCHAR(16) NOT NULL,
+    actor_user_id BIGINT UNSIGNED NULL DEFAULT NULL,
+    reason TEXT,
+    CONSTRAINT fk_meeting_status_changes_meeting
+        FOREIGN KEY (meeting_id) REFERENCES meetings(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_meeting_status_changes_actor
+        FOREIGN KEY (actor_user_id) REFERENCES users(id)
+        ON DELETE SET NULL,
+    CONSTRAINT valid_meeting_status_change_actor CHECK (actor IN ('advisor', 'invitee', 'system'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create slot_holds table
+CREATE TABLE slot_holds (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    scheduling_link_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_slot_holds_scheduling_link
+        FOREIGN KEY (scheduling_link_id) REFERENCES scheduling_links(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_slot_holds_user
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT valid_slot_hold_range CHECK (start_time < end_time)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create waitlist_entries table
+CREATE TABLE waitlist_entries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    scheduling_link_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    date VARCHAR(10) NULL DEFAULT NULL,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    status VARCHAR(16) NOT NULL DEFAULT 'waiting',
+    hold_token VARCHAR(64) NULL DEFAULT NULL UNIQUE,
+    offered_start TIMESTAMP NULL DEFAULT NULL,
+    offered_end TIMESTAMP NULL DEFAULT NULL,
+    offer_expires_at TIMESTAMP NULL DEFAULT NULL,
+    CONSTRAINT fk_waitlist_entries_scheduling_link
+        FOREIGN KEY (scheduling_link_id) REFERENCES scheduling_links(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_waitlist_entries_user
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT valid_waitlist_status CHECK (status IN ('waiting', 'offered', 'claimed', 'expired'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create idempotency_keys table
+CREATE TABLE idempotency_keys (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    scope VARCHAR(64) NOT NULL,
+    `key` VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code SMALLINT UNSIGNED NOT NULL,
+    response JSON NOT NULL,
+    CONSTRAINT unique_idempotency_scope_key UNIQUE (scope, `key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Create meeting_notes table
+CREATE TABLE meeting_notes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    meeting_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    body TEXT NOT NULL,
+    CONSTRAINT fk_meeting_notes_meeting
+        FOREIGN KEY (meeting_id) REFERENCES meetings(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_meeting_notes_user
+        FOREIGN KEY (user_id) REFERENCES users(id)
+        ON DELETE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- Create google_accounts table
@@ -141,11 +416,27 @@ CREATE TABLE hubspot_accounts (
 CREATE INDEX idx_users_email ON users(email);
 CREATE INDEX idx_users_google_id ON users(google_id);
 CREATE INDEX idx_users_hubspot_id ON users(hubspot_id);
+CREATE INDEX idx_schedules_user_id ON schedules(user_id);
 CREATE INDEX idx_scheduling_windows_user_id ON scheduling_windows(user_id);
+CREATE INDEX idx_scheduling_windows_schedule_id ON scheduling_windows(schedule_id);
+CREATE INDEX idx_scheduling_overrides_schedule_id ON scheduling_overrides(schedule_id);
+CREATE INDEX idx_scheduling_overrides_user_dates ON scheduling_overrides(user_id, start_date, end_date);
+CREATE INDEX idx_holiday_calendars_user_id ON holiday_calendars(user_id);
+CREATE INDEX idx_holidays_calendar_id ON holidays(holiday_calendar_id);
+CREATE INDEX idx_holidays_user_dates ON holidays(user_id, start_date, end_date);
 CREATE INDEX idx_scheduling_links_user_id ON scheduling_links(user_id);
 CREATE INDEX idx_meetings_scheduling_link_id ON meetings(scheduling_link_id);
 CREATE INDEX idx_meetings_user_id ON meetings(user_id);
+CREATE INDEX idx_meetings_client_id ON meetings(client_id);
 CREATE INDEX idx_meetings_start_time ON meetings(start_time);
+CREATE INDEX idx_meetings_series_id ON meetings(series_id);
+CREATE INDEX idx_meeting_series_user_id ON meeting_series(user_id);
+CREATE INDEX idx_meeting_notes_meeting_id ON meeting_notes(meeting_id);
+CREATE INDEX idx_meeting_status_changes_meeting_id ON meeting_status_changes(meeting_id);
+CREATE INDEX idx_slot_holds_user_expires ON slot_holds(user_id, expires_at);
+CREATE INDEX idx_waitlist_entries_link_status ON waitlist_entries(scheduling_link_id, status);
+CREATE INDEX idx_meetings_user_start_time ON meetings(user_id, start_time);
+CREATE INDEX idx_meetings_approval_deadline ON meetings(approval_deadline);
 CREATE INDEX idx_google_accounts_user_id ON google_accounts(user_id);
 CREATE INDEX idx_google_accounts_google_id ON google_accounts(google_id);
 CREATE INDEX idx_google_accounts_email ON google_accounts(email);
@@ -192,4 +483,5 @@ AND (max_uses IS NULL OR max_uses > (
     SELECT COUNT(*) FROM meetings 
     WHERE meetings.scheduling_link_id = scheduling_links.id
     AND meetings.deleted_at IS NULL
+    AND meetings.status IN ('pending', 'scheduled', 'completed', 'no_show')
 )); 
\ No newline at end of file
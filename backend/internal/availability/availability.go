@@ -0,0 +1,249 @@
+// Package availability computes bookable slots from an advisor's weekly windows, date overrides,
+// existing meetings, calendar busy time and a scheduling link's rules. It does no I/O: callers load
+// the inputs and the package works out which times can be offered and why others cannot.
+package availability
+
+import (
+	"sort"
+	"time"
+)
+
+// Interval is a span of time from Start up to, but not including, End
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// overlaps reports whether two intervals share any time
+func (i Interval) overlaps(other Interval) bool {
+	return i.Start.Before(other.End) && i.End.After(other.Start)
+}
+
+// contains reports whether other lies entirely within i
+func (i Interval) contains(other Interval) bool {
+	return !other.Start.Before(i.Start) && !other.End.After(i.End)
+}
+
+// Window is a weekly recurring period of availability
+type Window struct {
+	Weekday     time.Weekday
+	StartMinute int            // minutes after midnight
+	EndMinute   int            // minutes after midnight, up to 24*60
+	Location    *time.Location // nil means the advisor's time zone
+}
+
+// Override changes availability on a range of dates in the advisor's time zone. Available overrides
+// replace the weekly windows of the dates they cover; unavailable ones block time on top of them.
+type Override struct {
+	StartDate   string // yyyy-mm-dd, inclusive
+	EndDate     string // yyyy-mm-dd, inclusive
+	StartMinute *int   // nil together with EndMinute means the whole day
+	EndMinute   *int
+	Available   bool
+	Holiday     string // name of the holiday if the override comes from an imported holiday calendar
+}
+
+// allDay reports whether the override covers whole days rather than a time range
+func (o Override) allDay() bool {
+	return o.StartMinute == nil || o.EndMinute == nil
+}
+
+// Meeting is an existing booking of the advisor, or a hold an invitee has placed on a slot
+type Meeting struct {
+	Start  time.Time
+	End    time.Time
+	LinkID uint
+	Held   bool // a temporary hold while an invitee fills in the booking form
+}
+
+// Rules are the constraints of a scheduling link
+type Rules struct {
+	LinkID           uint
+	Inactive         bool // the link has been deactivated
+	Duration         time.Duration
+	Interval         time.Duration // time between slot starts; zero means Duration
+	TopOfHour        bool          // slots only start on the hour
+	Seats            int           // attendees per slot; 0 or 1 means one-on-one meetings
+	BufferBefore     time.Duration
+	BufferAfter      time.Duration
+	MinNotice        time.Duration
+	Ranking          string     // how offered slots are ranked, one of the Ranking modes; empty means RankingNone
+	Horizon          string     // how far ahead slots are offered, one of the Horizon modes; empty means HorizonRolling
+	MaxDaysInAdvance int        // days from today in the advisor's time zone, for the rolling modes
+	StartDate        string     // yyyy-mm-dd first bookable date in the advisor's time zone, for HorizonFixed
+	EndDate          string     // yyyy-mm-dd last bookable date in the advisor's time zone, for HorizonFixed
+	ExpiresAt        *time.Time // no slots start after this
+	MaxUses          *int       // total meetings the link may book
+	MaxPerDay        *int       // meetings of this link per day in the advisor's time zone
+	MaxPerWeek       *int       // meetings of this link per Sunday-Saturday week in the advisor's time zone
+}
+
+// interval returns the spacing between slot starts
+func (r Rules) interval() time.Duration {
+	if r.TopOfHour {
+		return time.Hour
+	}
+	if r.Interval <= 0 {
+		return r.Duration
+	}
+	return r.Interval
+}
+
+// Horizon modes
+const (
+	HorizonRolling      = "rolling"       // MaxDaysInAdvance calendar days from today
+	HorizonBusinessDays = "business_days" // MaxDaysInAdvance weekdays from today
+	HorizonFixed        = "fixed"         // from StartDate to EndDate
+)
+
+// BookableDates returns midnight of the first and last dates in loc on which slots may start. The
+// last date is before the first if no date is bookable any more.
+func (r Rules) BookableDates(now time.Time, loc *time.Location) (time.Time, time.Time) {
+	today := StartOfDay(now, loc)
+
+	switch r.Horizon {
+	case HorizonFixed:
+		first, err := time.ParseInLocation("2006-01-02", r.StartDate, loc)
+		if err != nil || first.Before(today) {
+			first = today
+		}
+		last, err := time.ParseInLocation("2006-01-02", r.EndDate, loc)
+		if err != nil {
+			last = today.AddDate(0, 0, -1)
+		}
+		return first, last
+	case HorizonBusinessDays:
+		// Count forward over weekdays only, so a horizon of 5 from a Friday reaches the next Friday
+		last := today
+		for remaining := r.MaxDaysInAdvance; remaining > 0; {
+			last = last.AddDate(0, 0, 1)
+			if last.Weekday() != time.Saturday && last.Weekday() != time.Sunday {
+				remaining--
+			}
+		}
+		return today, last
+	default:
+		return today, today.AddDate(0, 0, r.MaxDaysInAdvance)
+	}
+}
+
+// seats returns how many attendees can book each slot
+func (r Rules) seats() int {
+	if r.Seats < 1 {
+		return 1
+	}
+	return r.Seats
+}
+
+// Input is everything needed to compute availability. Meetings should be all of the advisor's
+// live meetings, whichever link booked them, and cover the whole weeks being computed so that
+// the weekly cap can be counted.
+type Input struct {
+	Location  *time.Location // advisor's time zone
+	Windows   []Window
+	Overrides []Override
+	Meetings  []Meeting
+	Busy      []Interval
+	Uses      int // meetings the link has booked so far, for MaxUses
+	Rules     Rules
+	Now       time.Time
+}
+
+// Slot is a bookable slot and how many of its seats are still free
+type Slot struct {
+	Interval
+	SeatsLeft   int
+	Score       float64 // from 0 to 1, how tightly the slot packs against other commitments; only set when ranking
+	Recommended bool    // one of the best scoring slots of the advisor's day; only set when ranking
+}
+
+// Day is the outcome of computing one day
+type Day struct {
+	Date       time.Time // midnight starting the day, in the requested time zone
+	HasWindows bool      // whether any availability falls on the day before meetings are removed
+	Slots      []Slot    // bookable slots in chronological order
+}
+
+// Day computes the bookable slots starting within the day that begins at dayStart. The day is
+// taken in dayStart's time zone, which may differ from the advisor's. Ranked slots are always ranked
+// within the advisor's day they fall on, so every time zone is offered the same recommendations.
+func (in Input) Day(dayStart time.Time) Day {
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	slots, hasWindows := in.slots(dayStart, dayEnd)
+	if in.Rules.Ranked() {
+		slots = []Slot{}
+		for date := StartOfDay(dayStart, in.Location); date.Before(dayEnd); date = date.AddDate(0, 0, 1) {
+			advisorSlots, _ := in.slots(date, date.AddDate(0, 0, 1))
+			for _, slot := range in.recommend(advisorSlots) {
+				if !slot.Start.Before(dayStart) && slot.Start.Before(dayEnd) {
+					slots = append(slots, slot)
+				}
+			}
+		}
+	}
+	return Day{Date: dayStart, HasWindows: hasWindows, Slots: slots}
+}
+
+// slots lays out the bookable slots starting within [dayStart, dayEnd) in chronological order,
+// scored if the link is ranked, and reports whether any availability touches the range
+func (in Input) slots(dayStart, dayEnd time.Time) ([]Slot, bool) {
+	ranges := in.ranges(dayStart, dayEnd, true)
+	slots := []Slot{}
+	for _, window := range ranges {
+		for _, slot := range in.gridSlots(window) {
+			if slot.Start.Before(dayStart) || !slot.Start.Before(dayEnd) {
+				continue
+			}
+			if e := in.Check(slot); e.Available {
+				ranked := Slot{Interval: slot, SeatsLeft: e.SeatsLeft}
+				if in.Rules.Ranked() {
+					ranked.Score = in.score(ranked, window)
+				}
+				slots = append(slots, ranked)
+			}
+		}
+	}
+
+	// Windows from different time zones may interleave, so order the slots chronologically
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].Start.Before(slots[j].Start)
+	})
+	return slots, len(ranges) > 0
+}
+
+// gridSlots lays out the slots of a window on the link's grid, each fitting the whole meeting
+func (in Input) gridSlots(window Interval) []Interval {
+	slots := []Interval{}
+	step := in.Rules.interval()
+	if step <= 0 || in.Rules.Duration <= 0 {
+		return slots
+	}
+	for start := in.firstStart(window.Start); !start.Add(in.Rules.Duration).After(window.End); start = start.Add(step) {
+		slots = append(slots, Interval{Start: start, End: start.Add(in.Rules.Duration)})
+	}
+	return slots
+}
+
+// firstStart returns the first slot start on the link's grid at or after the start of a window
+func (in Input) firstStart(windowStart time.Time) time.Time {
+	if !in.Rules.TopOfHour {
+		return windowStart
+	}
+	// Round up to the next full hour on the window's wall clock
+	if windowStart.Minute() == 0 && windowStart.Second() == 0 && windowStart.Nanosecond() == 0 {
+		return windowStart
+	}
+	return time.Date(windowStart.Year(), windowStart.Month(), windowStart.Day(), windowStart.Hour()+1, 0, 0, 0, windowStart.Location())
+}
+
+// StartOfDay returns midnight of t's date in loc
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// StartOfWeek returns midnight of the Sunday starting t's week in loc, matching the 0-6 (Sunday-Saturday) weekdays of windows
+func StartOfWeek(t time.Time, loc *time.Location) time.Time {
+	day := StartOfDay(t, loc)
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
@@ -0,0 +1,194 @@
+package availability
+
+import (
+	"testing"
+	"time"
+)
+
+// monday is a Monday in UTC, with now the evening before it
+var (
+	monday = time.Date(2030, time.January, 7, 0, 0, 0, 0, time.UTC)
+	now    = monday.Add(-12 * time.Hour)
+)
+
+// at returns the time on the test Monday, offset by days, at hour:minute in UTC
+func at(days, hour, minute int) time.Time {
+	return monday.AddDate(0, 0, days).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+// testInput is an advisor in UTC available 9:00 to 17:00 on weekdays, with a link for 30 minute
+// meetings bookable 30 days ahead
+func testInput() Input {
+	in := Input{
+		Location: time.UTC,
+		Now:      now,
+		Rules: Rules{
+			LinkID:           1,
+			Duration:         30 * time.Minute,
+			MaxDaysInAdvance: 30,
+		},
+	}
+	for weekday := time.Monday; weekday <= time.Friday; weekday++ {
+		in.Windows = append(in.Windows, Window{Weekday: weekday, StartMinute: 9 * 60, EndMinute: 17 * 60})
+	}
+	return in
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+// starts formats the start times of slots as hh:mm in loc, in order
+func starts(slots []Slot, loc *time.Location) []string {
+	result := make([]string, len(slots))
+	for i, slot := range slots {
+		result[i] = slot.Start.In(loc).Format("15:04")
+	}
+	return result
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDayKeepsWallClockWindowsAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	in := testInput()
+	in.Location = newYork
+	in.Now = time.Date(2030, time.March, 1, 12, 0, 0, 0, newYork)
+	in.Windows = []Window{
+		{Weekday: time.Monday, StartMinute: 9 * 60, EndMinute: 10 * 60},
+		{Weekday: time.Sunday, StartMinute: 0, EndMinute: 4 * 60},
+	}
+
+	// Clocks go forward at 2:00 on Sunday, March 10, 2030
+	tests := []struct {
+		name     string
+		day      time.Time
+		local    []string
+		firstUTC time.Time
+	}{
+		{"before the change", time.Date(2030, time.March, 4, 0, 0, 0, 0, newYork), []string{"09:00", "09:30"}, time.Date(2030, time.March, 4, 14, 0, 0, 0, time.UTC)},
+		{"after the change", time.Date(2030, time.March, 11, 0, 0, 0, 0, newYork), []string{"09:00", "09:30"}, time.Date(2030, time.March, 11, 13, 0, 0, 0, time.UTC)},
+		{"on the change", time.Date(2030, time.March, 10, 0, 0, 0, 0, newYork), []string{"00:00", "00:30", "01:00", "01:30", "03:00", "03:30"}, time.Date(2030, time.March, 10, 5, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		day := in.Day(tt.day)
+		if got := starts(day.Slots, newYork); !equalStrings(got, tt.local) {
+			t.Errorf("%s: got slots %v, want %v", tt.name, got, tt.local)
+			continue
+		}
+		if !day.Slots[0].Start.Equal(tt.firstUTC) {
+			t.Errorf("%s: first slot starts at %s, want %s", tt.name, day.Slots[0].Start.UTC(), tt.firstUTC)
+		}
+	}
+}
+
+func TestDayAppliesOverrides(t *testing.T) {
+	date := monday.Format("2006-01-02")
+	tests := []struct {
+		name       string
+		overrides  []Override
+		slots      []string
+		hasWindows bool
+	}{
+		{
+			"available override replaces the weekly windows",
+			[]Override{{StartDate: date, EndDate: date, StartMinute: intPtr(13 * 60), EndMinute: intPtr(14 * 60), Available: true}},
+			[]string{"13:00", "13:30"},
+			true,
+		},
+		{
+			"available override spanning several dates",
+			[]Override{{StartDate: "2030-01-05", EndDate: "2030-01-07", StartMinute: intPtr(8 * 60), EndMinute: intPtr(9 * 60), Available: true}},
+			[]string{"08:00", "08:30"},
+			true,
+		},
+		{
+			"unavailable override blocks part of the day",
+			[]Override{{StartDate: date, EndDate: date, StartMinute: intPtr(9 * 60), EndMinute: intPtr(16 * 60)}},
+			[]string{"16:00", "16:30"},
+			true,
+		},
+		{
+			"unavailable all-day override blocks the whole day",
+			[]Override{{StartDate: date, EndDate: date}},
+			[]string{},
+			false,
+		},
+		{
+			"overrides on other dates are ignored",
+			[]Override{{StartDate: "2030-01-08", EndDate: "2030-01-10"}},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		in := testInput()
+		in.Overrides = tt.overrides
+		day := in.Day(monday)
+		got := starts(day.Slots, time.UTC)
+		if tt.slots != nil && !equalStrings(got, tt.slots) {
+			t.Errorf("%s: got slots %v, want %v", tt.name, got, tt.slots)
+		}
+		if tt.slots == nil && len(got) != 16 {
+			t.Errorf("%s: got %d slots, want the 16 of the weekly window", tt.name, len(got))
+		}
+		if day.HasWindows != tt.hasWindows {
+			t.Errorf("%s: HasWindows is %v, want %v", tt.name, day.HasWindows, tt.hasWindows)
+		}
+	}
+}
+
+func TestDayLaysSlotsOnTheGrid(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  time.Duration
+		topOfHour bool
+		window    [2]int // start and end minute
+		count     int
+		first     string
+		last      string
+	}{
+		{"duration sets the step", 0, false, [2]int{9 * 60, 17 * 60}, 16, "09:00", "16:30"},
+		{"shorter interval overlaps slots", 15 * time.Minute, false, [2]int{9 * 60, 17 * 60}, 31, "09:00", "16:30"},
+		{"longer interval spaces slots out", 60 * time.Minute, false, [2]int{9 * 60, 17 * 60}, 8, "09:00", "16:00"},
+		{"top of hour rounds up the window start", 15 * time.Minute, true, [2]int{9*60 + 30, 17 * 60}, 7, "10:00", "16:00"},
+		{"slots must fit the whole meeting", 0, false, [2]int{9 * 60, 10*60 + 20}, 2, "09:00", "09:30"},
+	}
+	for _, tt := range tests {
+		in := testInput()
+		in.Rules.Interval = tt.interval
+		in.Rules.TopOfHour = tt.topOfHour
+		in.Windows = []Window{{Weekday: time.Monday, StartMinute: tt.window[0], EndMinute: tt.window[1]}}
+
+		got := starts(in.Day(monday).Slots, time.UTC)
+		if len(got) != tt.count || got[0] != tt.first || got[len(got)-1] != tt.last {
+			t.Errorf("%s: got %d slots from %v, want %d from %s to %s", tt.name, len(got), got, tt.count, tt.first, tt.last)
+		}
+	}
+}
+
+func TestDayInAnotherTimeZone(t *testing.T) {
+	// An invitee in UTC+10 sees the end of the advisor's Monday and the start of their Tuesday
+	tokyoish := time.FixedZone("UTC+10", 10*60*60)
+	in := testInput()
+
+	day := in.Day(time.Date(2030, time.January, 8, 0, 0, 0, 0, tokyoish))
+	got := starts(day.Slots, tokyoish)
+	if len(got) != 16 || got[0] != "00:00" || got[5] != "02:30" || got[6] != "19:00" || got[15] != "23:30" {
+		t.Errorf("got slots %v, want 00:00 to 02:30 and 19:00 to 23:30", got)
+	}
+}
@@ -0,0 +1,249 @@
+package availability
+
+import (
+	"fmt"
+	"time"
+)
+
+// Reason identifies the rule that keeps a slot from being offered
+type Reason string
+
+const (
+	ReasonLinkInactive      Reason = "link_inactive"
+	ReasonLinkExpired       Reason = "link_expired"
+	ReasonMaxUsesReached    Reason = "max_uses_reached"
+	ReasonInPast            Reason = "in_past"
+	ReasonMinNotice         Reason = "min_notice"
+	ReasonBeyondHorizon     Reason = "beyond_max_days_in_advance"
+	ReasonOutsideDateRange  Reason = "outside_date_range"
+	ReasonOutsideWindow     Reason = "outside_window"
+	ReasonBlockedByOverride Reason = "blocked_by_override"
+	ReasonHoliday           Reason = "holiday"
+	ReasonOffGrid           Reason = "off_grid"
+	ReasonMeetingConflict   Reason = "meeting_conflict"
+	ReasonHeld              Reason = "held"
+	ReasonFullyBooked       Reason = "fully_booked"
+	ReasonBusyCalendar      Reason = "busy_calendar"
+	ReasonBuffer            Reason = "buffer"
+	ReasonDailyCap          Reason = "daily_cap_reached"
+	ReasonWeeklyCap         Reason = "weekly_cap_reached"
+	ReasonNotRecommended    Reason = "not_recommended"
+)
+
+// Explanation says whether a slot can be offered and, if not, which rule excluded it
+type Explanation struct {
+	Slot      Interval
+	Available bool
+	Reason    Reason // empty when Available
+	Detail    string // human readable description of Reason
+	SeatsLeft int    // free seats when Available
+}
+
+// Explain checks a candidate slot starting at start against every rule, in the order the rules
+// are applied when slots are computed, and reports the first one that excludes it
+func (in Input) Explain(start time.Time) Explanation {
+	slot := Interval{Start: start, End: start.Add(in.Rules.Duration)}
+
+	if e := in.checkLink(slot); !e.Available {
+		return e
+	}
+	if e := in.checkWindow(slot); !e.Available {
+		return e
+	}
+	e := in.checkConflicts(slot)
+	if !e.Available {
+		return e
+	}
+	return in.checkRecommended(e)
+}
+
+// checkRecommended applies the compact_only ranking, which only offers the best scoring slots of
+// the advisor's day
+func (in Input) checkRecommended(e Explanation) Explanation {
+	if in.Rules.Ranking != RankingCompactOnly {
+		return e
+	}
+	for _, slot := range in.Day(StartOfDay(e.Slot.Start, in.Location)).Slots {
+		if slot.Start.Equal(e.Slot.Start) {
+			return e
+		}
+	}
+	return excluded(e.Slot, ReasonNotRecommended, "The link only offers the slots that pack the day most compactly")
+}
+
+// Check applies every rule except window placement and ranking, for callers that have already
+// placed the slot in a window, such as when laying out slots
+func (in Input) Check(slot Interval) Explanation {
+	if e := in.checkLink(slot); !e.Available {
+		return e
+	}
+	return in.checkConflicts(slot)
+}
+
+// checkLink applies the link-wide limits and the timing of the slot relative to now
+func (in Input) checkLink(slot Interval) Explanation {
+	r := in.Rules
+
+	if r.Inactive {
+		return excluded(slot, ReasonLinkInactive, "The link has been deactivated")
+	}
+	if r.ExpiresAt != nil && (in.Now.After(*r.ExpiresAt) || slot.Start.After(*r.ExpiresAt)) {
+		return excluded(slot, ReasonLinkExpired, "The link expires at %s", in.format(*r.ExpiresAt))
+	}
+	if r.MaxUses != nil && in.Uses >= *r.MaxUses {
+		return excluded(slot, ReasonMaxUsesReached, "The link has been used %d of %d times", in.Uses, *r.MaxUses)
+	}
+	if !slot.Start.After(in.Now) {
+		return excluded(slot, ReasonInPast, "The slot has already started")
+	}
+	if slot.Start.Before(in.Now.Add(r.MinNotice)) {
+		return excluded(slot, ReasonMinNotice, "The link requires %s notice, so the earliest start is %s", r.MinNotice, in.format(in.Now.Add(r.MinNotice)))
+	}
+
+	firstDay, lastDay := r.BookableDates(in.Now, in.Location)
+	day := StartOfDay(slot.Start, in.Location)
+	switch {
+	case r.Horizon == HorizonFixed && (day.Before(firstDay) || day.After(lastDay)):
+		return excluded(slot, ReasonOutsideDateRange, "The link can only be booked from %s to %s", r.StartDate, r.EndDate)
+	case r.Horizon == HorizonBusinessDays && day.After(lastDay):
+		return excluded(slot, ReasonBeyondHorizon, "The link can only be booked %d business days in advance, up to %s", r.MaxDaysInAdvance, lastDay.Format("2006-01-02"))
+	case day.After(lastDay):
+		return excluded(slot, ReasonBeyondHorizon, "The link can only be booked %d days in advance, up to %s", r.MaxDaysInAdvance, lastDay.Format("2006-01-02"))
+	}
+
+	return available(slot)
+}
+
+// checkWindow makes sure the slot fits a window on the advisor's day and lies on the link's grid
+func (in Input) checkWindow(slot Interval) Explanation {
+	dayStart := StartOfDay(slot.Start, in.Location)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	fits := false
+	for _, window := range in.ranges(dayStart, dayEnd, true) {
+		if !window.contains(slot) {
+			continue
+		}
+		fits = true
+		for _, candidate := range in.gridSlots(window) {
+			if candidate.Start.Equal(slot.Start) {
+				return available(slot)
+			}
+		}
+	}
+	if fits {
+		return excluded(slot, ReasonOffGrid, "Slots start every %s from the beginning of the window", in.Rules.interval())
+	}
+
+	// The slot would fit if it weren't for an unavailable override
+	for _, window := range in.ranges(dayStart, dayEnd, false) {
+		if !window.contains(slot) {
+			continue
+		}
+		for _, override := range in.overridesOn(dayStart) {
+			if !override.Available && override.Holiday != "" && overrideInterval(override, dayStart).overlaps(slot) {
+				return excluded(slot, ReasonHoliday, "The date is a holiday: %s", override.Holiday)
+			}
+		}
+		return excluded(slot, ReasonBlockedByOverride, "The time is blocked by a date override")
+	}
+	return excluded(slot, ReasonOutsideWindow, "The slot does not fit within any scheduling window")
+}
+
+// checkConflicts looks for meetings and busy calendar time, including the link's buffers, and
+// applies the seat limit and the daily and weekly caps
+func (in Input) checkConflicts(slot Interval) Explanation {
+	r := in.Rules
+
+	// Other attendees of the same session share the slot rather than conflicting with it
+	attendees := 0
+	for _, meeting := range in.Meetings {
+		if in.sameSession(meeting, slot) {
+			attendees++
+			continue
+		}
+		m := Interval{Start: meeting.Start, End: meeting.End}
+		if meeting.Held && (m.overlaps(slot) || meeting.Start.Equal(slot.Start)) {
+			return excluded(slot, ReasonHeld, "Held by another invitee from %s to %s", in.format(m.Start), in.format(m.End))
+		}
+		if m.overlaps(slot) || meeting.Start.Equal(slot.Start) {
+			return excluded(slot, ReasonMeetingConflict, "Overlaps a meeting from %s to %s", in.format(m.Start), in.format(m.End))
+		}
+	}
+	if attendees >= r.seats() {
+		return excluded(slot, ReasonFullyBooked, "All %d seats of the session are taken", r.seats())
+	}
+
+	for _, busy := range in.Busy {
+		if busy.overlaps(slot) {
+			return excluded(slot, ReasonBusyCalendar, "Overlaps busy time on a connected calendar from %s to %s", in.format(busy.Start), in.format(busy.End))
+		}
+	}
+
+	// The buffers have to be free as well as the meeting itself
+	buffered := Interval{Start: slot.Start.Add(-r.BufferBefore), End: slot.End.Add(r.BufferAfter)}
+	for _, meeting := range in.Meetings {
+		if in.sameSession(meeting, slot) {
+			continue
+		}
+		m := Interval{Start: meeting.Start, End: meeting.End}
+		if m.overlaps(buffered) {
+			return excluded(slot, ReasonBuffer, "The %s before and %s after the slot overlap a meeting from %s to %s", r.BufferBefore, r.BufferAfter, in.format(m.Start), in.format(m.End))
+		}
+	}
+	for _, busy := range in.Busy {
+		if busy.overlaps(buffered) {
+			return excluded(slot, ReasonBuffer, "The %s before and %s after the slot overlap busy calendar time from %s to %s", r.BufferBefore, r.BufferAfter, in.format(busy.Start), in.format(busy.End))
+		}
+	}
+
+	// Caps count sessions, so joining a session that already has attendees never exceeds them
+	if attendees == 0 && (r.MaxPerDay != nil || r.MaxPerWeek != nil) {
+		day := StartOfDay(slot.Start, in.Location)
+		week := StartOfWeek(slot.Start, in.Location)
+		perDay, perWeek := 0, 0
+		sessions := map[time.Time]bool{}
+		for _, meeting := range in.Meetings {
+			if meeting.LinkID != r.LinkID || sessions[meeting.Start.UTC()] {
+				continue
+			}
+			sessions[meeting.Start.UTC()] = true
+			if StartOfDay(meeting.Start, in.Location).Equal(day) {
+				perDay++
+			}
+			if StartOfWeek(meeting.Start, in.Location).Equal(week) {
+				perWeek++
+			}
+		}
+		if r.MaxPerDay != nil && perDay >= *r.MaxPerDay {
+			return excluded(slot, ReasonDailyCap, "The link allows %d meetings per day and %d are booked on %s", *r.MaxPerDay, perDay, day.Format("2006-01-02"))
+		}
+		if r.MaxPerWeek != nil && perWeek >= *r.MaxPerWeek {
+			return excluded(slot, ReasonWeeklyCap, "The link allows %d meetings per week and %d are booked in the week of %s", *r.MaxPerWeek, perWeek, week.Format("2006-01-02"))
+		}
+	}
+
+	e := available(slot)
+	e.SeatsLeft = r.seats() - attendees
+	return e
+}
+
+// sameSession reports whether a meeting is another attendee of a group session in the slot, which
+// needs a link with more than one seat and a booking through it for exactly the same time
+func (in Input) sameSession(meeting Meeting, slot Interval) bool {
+	return in.Rules.seats() > 1 && meeting.LinkID == in.Rules.LinkID &&
+		meeting.Start.Equal(slot.Start) && meeting.End.Equal(slot.End)
+}
+
+// format renders a time in the advisor's time zone for explanation details
+func (in Input) format(t time.Time) string {
+	return t.In(in.Location).Format("2006-01-02 15:04 MST")
+}
+
+func available(slot Interval) Explanation {
+	return Explanation{Slot: slot, Available: true}
+}
+
+func excluded(slot Interval, reason Reason, format string, args ...interface{}) Explanation {
+	return Explanation{Slot: slot, Reason: reason, Detail: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,269 @@
+package availability
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExplainCompactOnlyRejectsSlotsNotRecommended(t *testing.T) {
+	tests := []struct {
+		name    string
+		ranking string
+		start   time.Time
+		reason  Reason
+	}{
+		{"recommended before a meeting", RankingCompactOnly, at(0, 11, 30), ""},
+		{"recommended after a meeting", RankingCompactOnly, at(0, 12, 30), ""},
+		{"leaves gaps on both sides", RankingCompactOnly, at(0, 10, 0), ReasonNotRecommended},
+		{"at the edge of the window", RankingCompactOnly, at(0, 9, 0), ReasonNotRecommended},
+		{"compact still offers every slot", RankingCompact, at(0, 10, 0), ""},
+		{"unranked links offer every slot", RankingNone, at(0, 10, 0), ""},
+		{"conflicts are reported first", RankingCompactOnly, at(0, 12, 0), ReasonMeetingConflict},
+	}
+	for _, tt := range tests {
+		in := testInput()
+		in.Rules.Ranking = tt.ranking
+		in.Meetings = []Meeting{{Start: at(0, 12, 0), End: at(0, 12, 30), LinkID: 1}}
+
+		e := in.Explain(tt.start)
+		if e.Reason != tt.reason || e.Available != (tt.reason == "") {
+			t.Errorf("%s: got available %v with reason %q, want reason %q", tt.name, e.Available, e.Reason, tt.reason)
+		}
+	}
+}
+
+func TestExplainAgreesWithDayForCompactOnly(t *testing.T) {
+	in := testInput()
+	in.Rules.Ranking = RankingCompactOnly
+	in.Meetings = []Meeting{{Start: at(0, 14, 0), End: at(0, 15, 0), LinkID: 1}}
+
+	offered := map[time.Time]bool{}
+	for _, slot := range in.Day(monday).Slots {
+		offered[slot.Start] = true
+	}
+	if len(offered) == 0 {
+		t.Fatal("no slots were offered")
+	}
+	for start := at(0, 9, 0); start.Before(at(0, 17, 0)); start = start.Add(30 * time.Minute) {
+		if e := in.Explain(start); e.Available != offered[start] {
+			t.Errorf("%s: Explain says available %v (%s), Day offers it %v", start.Format("15:04"), e.Available, e.Reason, offered[start])
+		}
+	}
+}
+
+func TestExplainCompactOnlyAcrossTimeZones(t *testing.T) {
+	// An invitee in UTC+10 sees the end of the advisor's Monday and the start of their Tuesday, and
+	// is offered the advisor's recommendations for both
+	tokyoish := time.FixedZone("UTC+10", 10*60*60)
+	in := testInput()
+	in.Rules.Ranking = RankingCompactOnly
+	in.Meetings = []Meeting{{Start: at(0, 12, 0), End: at(0, 12, 30), LinkID: 2}}
+
+	tests := []struct {
+		name  string
+		day   time.Time
+		slots []string
+	}{
+		{"advisor's day", monday, []string{"11:30", "12:30"}},
+		{"invitee's day", time.Date(2030, time.January, 8, 0, 0, 0, 0, tokyoish), []string{"19:00"}},
+		{"invitee's previous day", time.Date(2030, time.January, 7, 0, 0, 0, 0, tokyoish), []string{"21:30", "22:30"}},
+	}
+	for _, tt := range tests {
+		offered := map[int64]bool{}
+		day := in.Day(tt.day)
+		for _, slot := range day.Slots {
+			offered[slot.Start.Unix()] = true
+		}
+		if got := starts(day.Slots, tt.day.Location()); !equalStrings(got, tt.slots) {
+			t.Errorf("%s: got slots %v, want %v", tt.name, got, tt.slots)
+		}
+
+		// Booking any time of the day must agree with what was offered
+		for start := tt.day; start.Before(tt.day.AddDate(0, 0, 1)); start = start.Add(30 * time.Minute) {
+			if e := in.Explain(start); e.Available != offered[start.Unix()] {
+				t.Errorf("%s: %s: Explain says available %v (%s), Day offers it %v", tt.name, start.Format("15:04"), e.Available, e.Reason, offered[start.Unix()])
+			}
+		}
+	}
+}
+
+func TestExplainBuffersAndNotice(t *testing.T) {
+	tests := []struct {
+		name   string
+		now    time.Time
+		start  time.Time
+		reason Reason
+	}{
+		{"clear of the buffers", now, at(0, 11, 0), ""},
+		{"buffer after runs into the meeting", now, at(0, 11, 30), ReasonBuffer},
+		{"buffer before runs into the meeting", now, at(0, 12, 30), ReasonBuffer},
+		{"overlaps the meeting", now, at(0, 12, 0), ReasonMeetingConflict},
+		{"clear after the meeting", now, at(0, 13, 0), ""},
+		{"overlaps busy calendar time", now, at(0, 15, 0), ReasonBusyCalendar},
+		{"buffer runs into busy calendar time", now, at(0, 14, 30), ReasonBuffer},
+		{"within the minimum notice", at(0, 8, 0), at(0, 9, 30), ReasonMinNotice},
+		{"right at the minimum notice", at(0, 8, 0), at(0, 10, 0), ""},
+		{"already started", at(0, 10, 15), at(0, 10, 0), ReasonInPast},
+	}
+	for _, tt := range tests {
+		in := testInput()
+		in.Now = tt.now
+		in.Rules.BufferBefore = 15 * time.Minute
+		in.Rules.BufferAfter = 15 * time.Minute
+		in.Rules.MinNotice = 2 * time.Hour
+		in.Meetings = []Meeting{{Start: at(0, 12, 0), End: at(0, 12, 30), LinkID: 2}}
+		in.Busy = []Interval{{Start: at(0, 15, 10), End: at(0, 16, 0)}}
+
+		e := in.Explain(tt.start)
+		if e.Reason != tt.reason || e.Available != (tt.reason == "") {
+			t.Errorf("%s: got available %v with reason %q, want reason %q", tt.name, e.Available, e.Reason, tt.reason)
+		}
+	}
+}
+
+func TestExplainSeats(t *testing.T) {
+	attendee := Meeting{Start: at(0, 10, 0), End: at(0, 10, 30), LinkID: 1}
+	tests := []struct {
+		name      string
+		seats     int
+		meetings  []Meeting
+		start     time.Time
+		reason    Reason
+		seatsLeft int
+	}{
+		{"empty session", 3, nil, at(0, 10, 0), "", 3},
+		{"joining a session", 3, []Meeting{attendee, attendee}, at(0, 10, 0), "", 1},
+		{"full session", 3, []Meeting{attendee, attendee, attendee}, at(0, 10, 0), ReasonFullyBooked, 0},
+		{"one-on-one links conflict", 1, []Meeting{attendee}, at(0, 10, 0), ReasonMeetingConflict, 0},
+		{"another link's meeting at the same time", 3, []Meeting{{Start: at(0, 10, 0), End: at(0, 10, 30), LinkID: 2}}, at(0, 10, 0), ReasonMeetingConflict, 0},
+		{"a session of a different length", 3, []Meeting{{Start: at(0, 10, 0), End: at(0, 11, 0), LinkID: 1}}, at(0, 10, 0), ReasonMeetingConflict, 0},
+		{"held seat", 3, []Meeting{{Start: at(0, 10, 0), End: at(0, 10, 30), LinkID: 1, Held: true}}, at(0, 10, 0), "", 2},
+	}
+	for _, tt := range tests {
+		in := testInput()
+		in.Rules.Seats = tt.seats
+		in.Meetings = tt.meetings
+
+		e := in.Explain(tt.start)
+		if e.Reason != tt.reason || e.Available != (tt.reason == "") || e.SeatsLeft != tt.seatsLeft {
+			t.Errorf("%s: got available %v with reason %q and %d seats left, want reason %q and %d seats left", tt.name, e.Available, e.Reason, e.SeatsLeft, tt.reason, tt.seatsLeft)
+		}
+	}
+}
+
+func TestExplainCaps(t *testing.T) {
+	booked := []Meeting{
+		{Start: at(0, 9, 0), End: at(0, 9, 30), LinkID: 1},
+		{Start: at(1, 9, 0), End: at(1, 9, 30), LinkID: 1},
+		{Start: at(1, 10, 0), End: at(1, 10, 30), LinkID: 2}, // other links don't count
+	}
+	tests := []struct {
+		name       string
+		maxPerDay  *int
+		maxPerWeek *int
+		start      time.Time
+		reason     Reason
+	}{
+		{"under the daily cap", intPtr(2), nil, at(0, 11, 0), ""},
+		{"daily cap reached", intPtr(1), nil, at(1, 11, 0), ReasonDailyCap},
+		{"weekly cap reached", nil, intPtr(2), at(2, 11, 0), ReasonWeeklyCap},
+		{"next week is under the weekly cap", nil, intPtr(2), at(7, 11, 0), ""},
+	}
+	for _, tt := range tests {
+		in := testInput()
+		in.Rules.MaxPerDay = tt.maxPerDay
+		in.Rules.MaxPerWeek = tt.maxPerWeek
+		in.Meetings = booked
+
+		e := in.Explain(tt.start)
+		if e.Reason != tt.reason || e.Available != (tt.reason == "") {
+			t.Errorf("%s: got available %v with reason %q, want reason %q", tt.name, e.Available, e.Reason, tt.reason)
+		}
+	}
+}
+
+func TestExplainHorizons(t *testing.T) {
+	// A Friday, so business days skip the weekend
+	friday := at(-3, 12, 0)
+	tests := []struct {
+		name    string
+		horizon string
+		days    int
+		from    string
+		to      string
+		start   time.Time
+		reason  Reason
+	}{
+		{"rolling, last day", HorizonRolling, 5, "", "", at(2, 10, 0), ""},
+		{"rolling, past the last day", HorizonRolling, 5, "", "", at(3, 10, 0), ReasonBeyondHorizon},
+		{"business days, last day", HorizonBusinessDays, 5, "", "", at(4, 10, 0), ""},
+		{"business days, past the last day", HorizonBusinessDays, 5, "", "", at(7, 10, 0), ReasonBeyondHorizon},
+		{"fixed, before the range", HorizonFixed, 0, "2030-01-08", "2030-01-09", at(0, 10, 0), ReasonOutsideDateRange},
+		{"fixed, first day", HorizonFixed, 0, "2030-01-08", "2030-01-09", at(1, 10, 0), ""},
+		{"fixed, last day", HorizonFixed, 0, "2030-01-08", "2030-01-09", at(2, 16, 30), ""},
+		{"fixed, after the range", HorizonFixed, 0, "2030-01-08", "2030-01-09", at(3, 10, 0), ReasonOutsideDateRange},
+	}
+	for _, tt := range tests {
+		in := testInput()
+		in.Now = friday
+		in.Rules.Horizon = tt.horizon
+		in.Rules.MaxDaysInAdvance = tt.days
+		in.Rules.StartDate = tt.from
+		in.Rules.EndDate = tt.to
+
+		e := in.Explain(tt.start)
+		if e.Reason != tt.reason || e.Available != (tt.reason == "") {
+			t.Errorf("%s: got available %v with reason %q, want reason %q", tt.name, e.Available, e.Reason, tt.reason)
+		}
+	}
+}
+
+func TestExplainLinkLimits(t *testing.T) {
+	expires := at(0, 12, 0)
+	tests := []struct {
+		name   string
+		modify func(in *Input)
+		reason Reason
+	}{
+		{"deactivated", func(in *Input) { in.Rules.Inactive = true }, ReasonLinkInactive},
+		{"expires before the slot", func(in *Input) { in.Rules.ExpiresAt = &expires }, ReasonLinkExpired},
+		{"uses left", func(in *Input) { in.Rules.MaxUses = intPtr(2); in.Uses = 1 }, ""},
+		{"out of uses", func(in *Input) { in.Rules.MaxUses = intPtr(2); in.Uses = 2 }, ReasonMaxUsesReached},
+	}
+	for _, tt := range tests {
+		in := testInput()
+		tt.modify(&in)
+
+		e := in.Explain(at(0, 13, 0))
+		if e.Reason != tt.reason || e.Available != (tt.reason == "") {
+			t.Errorf("%s: got available %v with reason %q, want reason %q", tt.name, e.Available, e.Reason, tt.reason)
+		}
+	}
+}
+
+func TestExplainWindowPlacement(t *testing.T) {
+	tests := []struct {
+		name   string
+		start  time.Time
+		reason Reason
+	}{
+		{"on the grid", at(0, 9, 30), ""},
+		{"off the grid", at(0, 9, 10), ReasonOffGrid},
+		{"runs past the window", at(0, 16, 45), ReasonOutsideWindow},
+		{"on a weekend", at(5, 10, 0), ReasonOutsideWindow},
+		{"blocked by an override", at(1, 10, 0), ReasonBlockedByOverride},
+		{"on a holiday", at(2, 10, 0), ReasonHoliday},
+	}
+	for _, tt := range tests {
+		in := testInput()
+		in.Overrides = []Override{
+			{StartDate: "2030-01-08", EndDate: "2030-01-08", StartMinute: intPtr(9 * 60), EndMinute: intPtr(12 * 60)},
+			{StartDate: "2030-01-09", EndDate: "2030-01-09", Holiday: "Test Day"},
+		}
+
+		e := in.Explain(tt.start)
+		if e.Reason != tt.reason || e.Available != (tt.reason == "") {
+			t.Errorf("%s: got available %v with reason %q, want reason %q", tt.name, e.Available, e.Reason, tt.reason)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package availability
+
+import "time"
+
+// ranges works out when the advisor is available around [dayStart, dayEnd). Weekly windows are
+// placed on the matching dates, each in its own time zone (or the advisor's), so a day requested in
+// another zone may pick up windows from two of the advisor's weekdays. Date overrides are then
+// applied in the advisor's time zone: available overrides replace the weekly windows of the dates
+// they cover, and, if applyBlocks is set, unavailable overrides cut their time out of what remains.
+func (in Input) ranges(dayStart, dayEnd time.Time, applyBlocks bool) []Interval {
+	// The advisor's dates touched by the requested day
+	dates := []time.Time{}
+	for date := StartOfDay(dayStart, in.Location); date.Before(dayEnd); date = date.AddDate(0, 0, 1) {
+		dates = append(dates, date)
+	}
+
+	// Dates where available overrides take precedence over the weekly windows
+	replaced := map[string]bool{}
+	for _, date := range dates {
+		for _, override := range in.overridesOn(date) {
+			if override.Available {
+				replaced[date.Format("2006-01-02")] = true
+			}
+		}
+	}
+
+	ranges := []Interval{}
+	for _, window := range in.Windows {
+		loc := window.Location
+		if loc == nil {
+			loc = in.Location
+		}
+		last := dayEnd.In(loc)
+		for date := StartOfDay(dayStart, loc); date.Before(last); date = date.AddDate(0, 0, 1) {
+			if date.Weekday() != window.Weekday {
+				continue
+			}
+			// time.Date resolves wall clock times, so windows keep their local hours across DST changes
+			start := time.Date(date.Year(), date.Month(), date.Day(), 0, window.StartMinute, 0, 0, loc)
+			end := time.Date(date.Year(), date.Month(), date.Day(), 0, window.EndMinute, 0, 0, loc)
+			if replaced[start.In(in.Location).Format("2006-01-02")] {
+				continue
+			}
+			ranges = append(ranges, Interval{Start: start, End: end})
+		}
+	}
+
+	for _, date := range dates {
+		for _, override := range in.overridesOn(date) {
+			if override.Available {
+				ranges = append(ranges, overrideInterval(override, date))
+			}
+		}
+	}
+	if applyBlocks {
+		for _, date := range dates {
+			for _, override := range in.overridesOn(date) {
+				if !override.Available {
+					ranges = subtract(ranges, overrideInterval(override, date))
+				}
+			}
+		}
+	}
+
+	// Only keep ranges that touch the requested day
+	day := Interval{Start: dayStart, End: dayEnd}
+	inDay := []Interval{}
+	for _, r := range ranges {
+		if r.overlaps(day) {
+			inDay = append(inDay, r)
+		}
+	}
+	return inDay
+}
+
+// overridesOn returns the overrides covering a date in the advisor's time zone
+func (in Input) overridesOn(date time.Time) []Override {
+	day := date.Format("2006-01-02")
+	matching := []Override{}
+	for _, override := range in.Overrides {
+		// Dates are yyyy-mm-dd, so they compare correctly as strings
+		if override.StartDate <= day && day <= override.EndDate {
+			matching = append(matching, override)
+		}
+	}
+	return matching
+}
+
+// overrideInterval places an override on one of the dates it covers
+func overrideInterval(override Override, date time.Time) Interval {
+	if override.allDay() {
+		return Interval{Start: date, End: date.AddDate(0, 0, 1)}
+	}
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, *override.StartMinute, 0, 0, date.Location())
+	end := time.Date(date.Year(), date.Month(), date.Day(), 0, *override.EndMinute, 0, 0, date.Location())
+	return Interval{Start: start, End: end}
+}
+
+// subtract removes a blocked span from a set of ranges, splitting ranges where needed
+func subtract(ranges []Interval, blocked Interval) []Interval {
+	result := []Interval{}
+	for _, r := range ranges {
+		if !r.overlaps(blocked) {
+			result = append(result, r)
+			continue
+		}
+		if r.Start.Before(blocked.Start) {
+			result = append(result, Interval{Start: r.Start, End: blocked.Start})
+		}
+		if r.End.After(blocked.End) {
+			result = append(result, Interval{Start: blocked.End, End: r.End})
+		}
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package availability
+
+import "time"
+
+// Ranking modes
+const (
+	RankingNone        = "none"         // slots are offered unscored
+	RankingCompact     = "compact"      // every slot is offered with a score, recommending the most compact
+	RankingCompactOnly = "compact_only" // only the recommended slots are offered
+)
+
+// Scores for each side of a slot
+const (
+	scoreAdjacent = 1.0  // touches a meeting or busy event
+	scoreEdge     = 0.5  // touches the start or end of the available time
+	scoreOpen     = 0.25 // leaves enough time for another meeting
+	scoreFragment = 0.0  // leaves a gap too short for another meeting
+)
+
+// Ranked reports whether offered slots are scored
+func (r Rules) Ranked() bool {
+	return r.Ranking == RankingCompact || r.Ranking == RankingCompactOnly
+}
+
+// score rates how little a slot would fragment the advisor's day, averaging what it leaves before
+// and after itself within the available range it belongs to. Joining a group session that already
+// has attendees adds nothing to the calendar, so it always scores highest.
+func (in Input) score(slot Slot, window Interval) float64 {
+	r := in.Rules
+	if slot.SeatsLeft < r.seats() {
+		return scoreAdjacent
+	}
+
+	commitments := append([]Interval{}, in.Busy...)
+	for _, meeting := range in.Meetings {
+		commitments = append(commitments, Interval{Start: meeting.Start, End: meeting.End})
+	}
+
+	// Find the nearest commitment or range edge on each side of the slot and its buffers
+	start, end := slot.Start.Add(-r.BufferBefore), slot.End.Add(r.BufferAfter)
+	before, beforeCommitment := window.Start, false
+	after, afterCommitment := window.End, false
+	for _, c := range commitments {
+		if !c.End.After(start) && !c.End.Before(before) {
+			before, beforeCommitment = c.End, true
+		}
+		if !c.Start.Before(end) && !c.Start.After(after) {
+			after, afterCommitment = c.Start, true
+		}
+	}
+
+	return (in.sideScore(start.Sub(before), beforeCommitment) + in.sideScore(after.Sub(end), afterCommitment)) / 2
+}
+
+// sideScore rates the gap a slot leaves on one side. A gap shorter than the grid step means no
+// other slot could be placed closer, so the slot counts as touching what lies beyond the gap.
+func (in Input) sideScore(gap time.Duration, commitment bool) float64 {
+	switch {
+	case gap < in.Rules.interval():
+		if commitment {
+			return scoreAdjacent
+		}
+		return scoreEdge
+	case gap < in.Rules.Duration:
+		return scoreFragment
+	default:
+		return scoreOpen
+	}
+}
+
+// recommend marks the day's best scoring slots as recommended, and drops the others if the link only
+// offers recommended slots. A day with nothing to pack against recommends the slots at the edges of
+// its available time, and a day where every slot would leave gaps recommends all of them, as none
+// packs the day better than another.
+func (in Input) recommend(slots []Slot) []Slot {
+	best := 0.0
+	for _, slot := range slots {
+		if slot.Score > best {
+			best = slot.Score
+		}
+	}
+
+	kept := []Slot{}
+	for _, slot := range slots {
+		slot.Recommended = slot.Score == best
+		if slot.Recommended || in.Rules.Ranking != RankingCompactOnly {
+			kept = append(kept, slot)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,138 @@
+package availability
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDayScoresSlots(t *testing.T) {
+	in := testInput()
+	in.Rules.Ranking = RankingCompact
+	in.Meetings = []Meeting{{Start: at(0, 12, 0), End: at(0, 12, 30), LinkID: 2}}
+
+	scores := map[string]float64{}
+	recommended := []string{}
+	for _, slot := range in.Day(monday).Slots {
+		start := slot.Start.Format("15:04")
+		scores[start] = slot.Score
+		if slot.Recommended {
+			recommended = append(recommended, start)
+		}
+	}
+
+	tests := []struct {
+		start string
+		score float64
+	}{
+		{"09:00", (scoreEdge + scoreOpen) / 2},
+		{"10:00", scoreOpen},
+		{"11:00", scoreOpen},
+		{"11:30", (scoreOpen + scoreAdjacent) / 2},
+		{"12:30", (scoreAdjacent + scoreOpen) / 2},
+		{"16:30", (scoreOpen + scoreEdge) / 2},
+	}
+	for _, tt := range tests {
+		if scores[tt.start] != tt.score {
+			t.Errorf("%s scored %v, want %v", tt.start, scores[tt.start], tt.score)
+		}
+	}
+	if !equalStrings(recommended, []string{"11:30", "12:30"}) {
+		t.Errorf("recommended %v, want the slots next to the meeting", recommended)
+	}
+	if len(scores) != 15 {
+		t.Errorf("compact offered %d slots, want every free slot", len(scores))
+	}
+}
+
+func TestDayRanking(t *testing.T) {
+	tests := []struct {
+		name     string
+		ranking  string
+		meetings []Meeting
+		busy     []Interval
+		slots    []string // nil means every free slot, unscored
+	}{
+		{"unranked", RankingNone, []Meeting{{Start: at(0, 12, 0), End: at(0, 12, 30), LinkID: 2}}, nil, nil},
+		{"compact only keeps the recommended slots", RankingCompactOnly, []Meeting{{Start: at(0, 12, 0), End: at(0, 12, 30), LinkID: 2}}, nil, []string{"11:30", "12:30"}},
+		{"an empty day recommends its edges", RankingCompactOnly, nil, nil, []string{"09:00", "16:30"}},
+		{"busy calendar time counts as a commitment", RankingCompactOnly, nil, []Interval{{Start: at(0, 14, 0), End: at(0, 15, 0)}}, []string{"13:30", "15:00"}},
+		{"a gap shorter than the grid step counts as touching", RankingCompactOnly, []Meeting{
+			{Start: at(0, 9, 0), End: at(0, 10, 0), LinkID: 2},
+			{Start: at(0, 10, 45), End: at(0, 17, 0), LinkID: 2},
+		}, nil, []string{"10:00"}},
+	}
+	for _, tt := range tests {
+		in := testInput()
+		in.Rules.Ranking = tt.ranking
+		in.Meetings = tt.meetings
+		in.Busy = tt.busy
+
+		slots := in.Day(monday).Slots
+		if tt.slots == nil {
+			if len(slots) != 15 {
+				t.Errorf("%s: got %d slots, want 15", tt.name, len(slots))
+			}
+			for _, slot := range slots {
+				if slot.Score != 0 || slot.Recommended {
+					t.Errorf("%s: %s was scored %v", tt.name, slot.Start.Format("15:04"), slot.Score)
+				}
+			}
+			continue
+		}
+		if got := starts(slots, time.UTC); !equalStrings(got, tt.slots) {
+			t.Errorf("%s: got slots %v, want %v", tt.name, got, tt.slots)
+		}
+	}
+}
+
+func TestDayRanksJoiningASessionHighest(t *testing.T) {
+	in := testInput()
+	in.Rules.Ranking = RankingCompactOnly
+	in.Rules.Seats = 4
+	in.Meetings = []Meeting{{Start: at(0, 10, 0), End: at(0, 10, 30), LinkID: 1}}
+
+	slots := in.Day(monday).Slots
+	if got := starts(slots, time.UTC); !equalStrings(got, []string{"10:00"}) {
+		t.Fatalf("got slots %v, want only the session", got)
+	}
+	if slots[0].Score != scoreAdjacent || slots[0].SeatsLeft != 3 {
+		t.Errorf("the session scored %v with %d seats left, want %v with 3", slots[0].Score, slots[0].SeatsLeft, scoreAdjacent)
+	}
+}
+
+func TestRecommendKeepsEverySlotWhenNoneScores(t *testing.T) {
+	fragmented := []Slot{
+		{Interval: Interval{Start: at(0, 9, 30), End: at(0, 10, 0)}},
+		{Interval: Interval{Start: at(0, 14, 30), End: at(0, 15, 0)}},
+	}
+	tests := []struct {
+		ranking string
+		slots   []Slot
+		want    []string
+	}{
+		{RankingCompactOnly, fragmented, []string{"09:30", "14:30"}},
+		{RankingCompact, fragmented, []string{"09:30", "14:30"}},
+		{RankingCompactOnly, []Slot{
+			{Interval: Interval{Start: at(0, 9, 30), End: at(0, 10, 0)}},
+			{Interval: Interval{Start: at(0, 11, 0), End: at(0, 11, 30)}, Score: scoreOpen},
+		}, []string{"11:00"}},
+	}
+	for _, tt := range tests {
+		in := testInput()
+		in.Rules.Ranking = tt.ranking
+
+		slots := in.recommend(tt.slots)
+		recommended := []string{}
+		for _, slot := range slots {
+			if slot.Recommended {
+				recommended = append(recommended, slot.Start.Format("15:04"))
+			}
+		}
+		if !equalStrings(recommended, tt.want) {
+			t.Errorf("%s: recommended %v, want %v", tt.ranking, recommended, tt.want)
+		}
+		if tt.ranking == RankingCompactOnly && len(slots) != len(tt.want) {
+			t.Errorf("%s: offered %d slots, want %d", tt.ranking, len(slots), len(tt.want))
+		}
+	}
+}
@@ -295,5 +295,6 @@ func (h *AuthHandler) Profile(c *gin.Context) {
 		"updated_at":     userModel.UpdatedAt,
 		"is_active":      userModel.IsActive,
 		"last_login_at":  userModel.LastLoginAt,
+		"timezone":       userModel.Location().String(),
 	})
 }
\ No newline at end of file
@@ -42,6 +42,7 @@ func (h *GoogleHandler) Profile(c *gin.Context) {
 		"updated_at":     userModel.UpdatedAt,
 		"is_active":      userModel.IsActive,
 		"last_login_at":  userModel.LastLoginAt,
+		"timezone":       userModel.Location().String(),
 	})
 }
 
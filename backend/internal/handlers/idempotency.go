@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+	"gorm.io/gorm"
+)
+
+// idempotencyHeader names the header clients set to make retries of a request safe
+const idempotencyHeader = "Idempotency-Key"
+
+// idempotencyKeyTTL is how long a stored result is replayed. Results hold the invitee's manage
+// token, so they aren't kept any longer than retries need.
+const idempotencyKeyTTL = 24 * time.Hour
+
+// idempotentRequest identifies a request sent with an Idempotency-Key header
+type idempotentRequest struct {
+	scope string
+	key   string
+	hash  string
+}
+
+// readIdempotency reads the Idempotency-Key header of a request to the scope, hashing the body so a
+// reused key can be told apart from a retry. It returns nil if the header is not set, and writes the
+// error response and returns false if the request can't be read.
+func readIdempotency(c *gin.Context, scope string) (*idempotentRequest, bool) {
+	key := c.GetHeader(idempotencyHeader)
+	if key == "" {
+		return nil, true
+	}
+	if len(key) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+		return nil, false
+	}
+
+	// Read the body for the hash and put it back for binding
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request"})
+		return nil, false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	sum := sha256.Sum256(body)
+	return &idempotentRequest{scope: scope, key: key, hash: hex.EncodeToString(sum[:])}, true
+}
+
+// bookingResult is the response a booking transaction decided on
+type bookingResult struct {
+	status int
+	body   interface{}
+}
+
+// replay looks for the stored result of an earlier request with the same key. It must run after the
+// booking lock is taken, so a retry sent while the original is in flight waits for its result.
+func (r *idempotentRequest) replay(tx *gorm.DB) (*bookingResult, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	var stored models.IdempotencyKey
+	err := tx.Where("scope = ? AND `key` = ?", r.scope, r.key).First(&stored).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// An expired key is forgotten, so it can be used again
+	if idempotencyKeyExpired(stored, time.Now()) {
+		return nil, tx.Unscoped().Delete(&stored).Error
+	}
+
+	if stored.RequestHash != r.hash {
+		return &bookingResult{
+			status: http.StatusUnprocessableEntity,
+			body:   gin.H{"error": "This Idempotency-Key was already used for a different request"},
+		}, nil
+	}
+	return &bookingResult{status: stored.StatusCode, body: json.RawMessage(stored.Response)}, nil
+}
+
+// save stores the result of a successful request for later retries. Failed requests are not stored,
+// so they can be retried once whatever made them fail is fixed.
+func (r *idempotentRequest) save(tx *gorm.DB, result bookingResult) error {
+	if r == nil {
+		return nil
+	}
+
+	response, err := json.Marshal(result.body)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&models.IdempotencyKey{
+		Scope:       r.scope,
+		Key:         r.key,
+		RequestHash: r.hash,
+		StatusCode:  result.status,
+		Response:    string(response),
+	}).Error
+}
+
+// idempotencyKeyExpired reports whether a stored result is too old to replay
+func idempotencyKeyExpired(stored models.IdempotencyKey, now time.Time) bool {
+	return !now.Before(stored.CreatedAt.Add(idempotencyKeyTTL))
+}
+
+// PruneIdempotencyKeys deletes the stored results that are too old to replay. It is run
+// periodically by the server.
+func (h *SchedulingHandler) PruneIdempotencyKeys() error {
+	if err := h.db.Unscoped().Where("created_at <= ?", time.Now().Add(-idempotencyKeyTTL)).Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return fmt.Errorf("failed to delete expired idempotency keys: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+)
+
+// idempotencyContext builds the context of a booking request with the body and Idempotency-Key
+func idempotencyContext(body string, key string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/scheduling/links/1/meetings/public", strings.NewReader(body))
+	if key != "" {
+		c.Request.Header.Set(idempotencyHeader, key)
+	}
+	return c, w
+}
+
+func TestReadIdempotency(t *testing.T) {
+	retried := `{"client_email":"invitee@example.com","start_time":"2026-11-02T10:00:00Z"}`
+	first, _ := idempotencyContext(retried, "key-1")
+	original, ok := readIdempotency(first, "meeting:1")
+	if !ok || original == nil {
+		t.Fatalf("got %v, want the request read", original)
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		key      string
+		scope    string
+		sameHash bool
+	}{
+		{"a retry matches the original", retried, "key-1", "meeting:1", true},
+		{"a different body under the same key doesn't", `{"client_email":"invitee@example.com","start_time":"2026-11-02T11:00:00Z"}`, "key-1", "meeting:1", false},
+	}
+	for _, tt := range tests {
+		c, _ := idempotencyContext(tt.body, tt.key)
+		got, ok := readIdempotency(c, tt.scope)
+		if !ok || got == nil {
+			t.Errorf("%s: got %v, want the request read", tt.name, got)
+			continue
+		}
+		if got.scope != tt.scope || got.key != tt.key {
+			t.Errorf("%s: got scope %q and key %q, want %q and %q", tt.name, got.scope, got.key, tt.scope, tt.key)
+		}
+		if (got.hash == original.hash) != tt.sameHash {
+			t.Errorf("%s: got hash %s, original %s", tt.name, got.hash, original.hash)
+		}
+		// The body is put back for binding
+		if body, _ := io.ReadAll(c.Request.Body); string(body) != tt.body {
+			t.Errorf("%s: got body %q after reading, want %q", tt.name, body, tt.body)
+		}
+	}
+
+	c, _ := idempotencyContext(retried, "")
+	if got, ok := readIdempotency(c, "meeting:1"); !ok || got != nil {
+		t.Errorf("no key: got %v, want nothing to replay", got)
+	}
+
+	c, w := idempotencyContext(retried, strings.Repeat("k", 256))
+	if _, ok := readIdempotency(c, "meeting:1"); ok || w.Code != http.StatusBadRequest {
+		t.Errorf("long key: got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestIdempotencyKeyExpired(t *testing.T) {
+	now := time.Date(2026, 11, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		stored  time.Time
+		expired bool
+	}{
+		{"just stored", now, false},
+		{"within the TTL", now.Add(-idempotencyKeyTTL + time.Minute), false},
+		{"at the TTL", now.Add(-idempotencyKeyTTL), true},
+		{"past the TTL", now.Add(-48 * time.Hour), true},
+	}
+	for _, tt := range tests {
+		stored := models.IdempotencyKey{}
+		stored.CreatedAt = tt.stored
+		if got := idempotencyKeyExpired(stored, now); got != tt.expired {
+			t.Errorf("%s: got expired %v, want %v", tt.name, got, tt.expired)
+		}
+	}
+}
@@ -3,25 +3,31 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/availability"
 	"github.com/yourusername/advisor-scheduling/internal/models"
 	"github.com/yourusername/advisor-scheduling/internal/services"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SchedulingHandler struct {
 	db *gorm.DB
 	emailService *services.EmailService
+	calendarService *services.CalendarService
 }
 
-func NewSchedulingHandler(db *gorm.DB, emailService *services.EmailService) *SchedulingHandler {
+func NewSchedulingHandler(db *gorm.DB, emailService *services.EmailService, calendarService *services.CalendarService) *SchedulingHandler {
 	return &SchedulingHandler{
 		db: db,
 		emailService: emailService,
+		calendarService: calendarService,
 	}
 }
 
@@ -30,9 +36,26 @@ func (h *SchedulingHandler) CreateSchedulingLink(c *gin.Context) {
 	var input struct {
 		Title            string     `json:"title" binding:"required"`
 		Duration         int        `json:"duration" binding:"required"`
+		Seats            int        `json:"seats" binding:"omitempty,min=1,max=1000"` // defaults to 1
 		MaxUses          *int       `json:"max_uses"`
 		ExpiresAt        *time.Time `json:"expires_at"`
-		MaxDaysInAdvance int        `json:"max_days_in_advance" binding:"required"`
+		HorizonMode      string     `json:"horizon_mode" binding:"omitempty,oneof=rolling business_days fixed"` // defaults to rolling
+		MaxDaysInAdvance int        `json:"max_days_in_advance" binding:"required_unless=HorizonMode fixed,min=0"`
+		StartDate        *string    `json:"start_date"` // yyyy-mm-dd, required for the fixed mode
+		EndDate          *string    `json:"end_date"`   // yyyy-mm-dd, required for the fixed mode
+		BufferBefore     int        `json:"buffer_before" binding:"min=0"`
+		BufferAfter      int        `json:"buffer_after" binding:"min=0"`
+		MinNotice        int        `json:"min_notice" binding:"min=0"`
+		SlotInterval     int        `json:"slot_interval" binding:"omitempty,oneof=15 30 60"`
+		TopOfHourOnly    bool       `json:"top_of_hour_only"`
+		MaxPerDay        *int       `json:"max_per_day" binding:"omitempty,min=1"`
+		MaxPerWeek       *int       `json:"max_per_week" binding:"omitempty,min=1"`
+		MaxOccurrences   int        `json:"max_occurrences" binding:"min=0,max=52"` // 2 or more allows recurring series
+		SlotRanking      string     `json:"slot_ranking" binding:"omitempty,oneof=none compact compact_only"` // defaults to none
+		RequiresApproval bool       `json:"requires_approval"`
+		ApprovalHours    int        `json:"approval_hours" binding:"min=0,max=720"` // defaults to 24
+		MaxGuests        int        `json:"max_guests" binding:"min=0,max=10"`
+		ScheduleID       *uint      `json:"schedule_id"` // omit to use the default schedule
 		CustomQuestions  []string   `json:"custom_questions" binding:"required,min=1"`
 	}
 
@@ -49,14 +72,81 @@ func (h *SchedulingHandler) CreateSchedulingLink(c *gin.Context) {
 	}
 	customQuestionsJSON := string(jsonBytes)
 
+	if input.SlotRanking == "" {
+		input.SlotRanking = availability.RankingNone
+	}
+
+	if input.ApprovalHours == 0 {
+		input.ApprovalHours = defaultApprovalHours
+	}
+
+	// Fixed links are bookable between two dates, while the rolling modes look ahead from today
+	if input.HorizonMode == "" {
+		input.HorizonMode = availability.HorizonRolling
+	}
+	if input.HorizonMode == availability.HorizonFixed {
+		if input.StartDate == nil || input.EndDate == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date are required for a fixed date range"})
+			return
+		}
+		startDate, err := time.Parse("2006-01-02", *input.StartDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date format (expected: yyyy-mm-dd)"})
+			return
+		}
+		endDate, err := time.Parse("2006-01-02", *input.EndDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date format (expected: yyyy-mm-dd)"})
+			return
+		}
+		if endDate.Before(startDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+			return
+		}
+	} else {
+		if input.MaxDaysInAdvance < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "max_days_in_advance must be at least 1"})
+			return
+		}
+		input.StartDate, input.EndDate = nil, nil
+	}
+
 	userID := c.GetUint("user_id")
+	if input.ScheduleID != nil {
+		if _, err := h.resolveScheduleID(userID, input.ScheduleID); err != nil {
+			if errors.Is(err, errScheduleNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Schedule not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create scheduling link"})
+			return
+		}
+	}
+
 	link := &models.SchedulingLink{
 		UserID:           userID,
 		Title:            input.Title,
 		Duration:         input.Duration,
+		Seats:            input.Seats,
 		MaxUses:          input.MaxUses,
 		ExpiresAt:        input.ExpiresAt,
+		HorizonMode:      input.HorizonMode,
 		MaxDaysInAdvance: input.MaxDaysInAdvance,
+		StartDate:        input.StartDate,
+		EndDate:          input.EndDate,
+		BufferBefore:     input.BufferBefore,
+		BufferAfter:      input.BufferAfter,
+		MinNotice:        input.MinNotice,
+		SlotInterval:     input.SlotInterval,
+		TopOfHourOnly:    input.TopOfHourOnly,
+		MaxPerDay:        input.MaxPerDay,
+		MaxPerWeek:       input.MaxPerWeek,
+		MaxOccurrences:   input.MaxOccurrences,
+		SlotRanking:      input.SlotRanking,
+		RequiresApproval: input.RequiresApproval,
+		ApprovalHours:    input.ApprovalHours,
+		MaxGuests:        input.MaxGuests,
+		ScheduleID:       input.ScheduleID,
 		CustomQuestions:  customQuestionsJSON,
 		IsActive:         true,
 	}
@@ -73,16 +163,38 @@ func (h *SchedulingHandler) CreateSchedulingLink(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	c.JSON(http.StatusCreated, linkResponse(*link, customQuestions))
+}
+
+// linkResponse formats a scheduling link in snake_case
+func linkResponse(link models.SchedulingLink, customQuestions []string) gin.H {
+	return gin.H{
 		"id":                  link.ID,
-		"title":              link.Title,
-		"duration":           link.Duration,
-		"max_uses":           link.MaxUses,
-		"expires_at":         link.ExpiresAt,
+		"title":               link.Title,
+		"duration":            link.Duration,
+		"seats":               link.Seats,
+		"max_uses":            link.MaxUses,
+		"expires_at":          link.ExpiresAt,
+		"horizon_mode":        link.HorizonMode,
 		"max_days_in_advance": link.MaxDaysInAdvance,
-		"custom_questions":   customQuestions,
-		"is_active":          link.IsActive,
-	})
+		"start_date":          link.StartDate,
+		"end_date":            link.EndDate,
+		"buffer_before":       link.BufferBefore,
+		"buffer_after":        link.BufferAfter,
+		"min_notice":          link.MinNotice,
+		"slot_interval":       link.SlotInterval,
+		"top_of_hour_only":    link.TopOfHourOnly,
+		"max_per_day":         link.MaxPerDay,
+		"max_per_week":        link.MaxPerWeek,
+		"max_occurrences":     link.MaxOccurrences,
+		"slot_ranking":        link.SlotRanking,
+		"requires_approval":   link.RequiresApproval,
+		"approval_hours":      link.ApprovalHours,
+		"max_guests":          link.MaxGuests,
+		"schedule_id":         link.ScheduleID,
+		"custom_questions":    customQuestions,
+		"is_active":           link.IsActive,
+	}
 }
 
 // GetSchedulingLink retrieves a scheduling link by ID
@@ -105,24 +217,19 @@ func (h *SchedulingHandler) GetSchedulingLink(c *gin.Context) {
 	}
 
 	// Return response in snake_case format
-	c.JSON(http.StatusOK, gin.H{
-		"id":                  link.ID,
-		"title":              link.Title,
-		"duration":           link.Duration,
-		"max_uses":           link.MaxUses,
-		"expires_at":         link.ExpiresAt,
-		"max_days_in_advance": link.MaxDaysInAdvance,
-		"custom_questions":   customQuestions,
-		"is_active":          link.IsActive,
-	})
+	c.JSON(http.StatusOK, linkResponse(link, customQuestions))
 }
 
 // CreateSchedulingWindow creates a new scheduling window
 func (h *SchedulingHandler) CreateSchedulingWindow(c *gin.Context) {
 	var input struct {
-		StartHour int `json:"start_hour" binding:"required"`
-		EndHour   int `json:"end_hour" binding:"required"`
-		Weekday   int `json:"weekday" binding:"required"`
+		StartHour   int    `json:"start_hour" binding:"required"`
+		StartMinute int    `json:"start_minute" binding:"min=0,max=59"`
+		EndHour     int    `json:"end_hour" binding:"required"`
+		EndMinute   int    `json:"end_minute" binding:"min=0,max=59"`
+		Weekday     int    `json:"weekday" binding:"required"`
+		Timezone    string `json:"timezone"`
+		ScheduleID  *uint  `json:"schedule_id"` // omit to use the default schedule
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -130,121 +237,120 @@ func (h *SchedulingHandler) CreateSchedulingWindow(c *gin.Context) {
 		return
 	}
 
+	// Windows must start before they end within a single day
+	startMinutes := input.StartHour*60 + input.StartMinute
+	endMinutes := input.EndHour*60 + input.EndMinute
+	if startMinutes < 0 || endMinutes > 24*60 || startMinutes >= endMinutes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window time range"})
+		return
+	}
+
+	// An empty time zone means the window follows the user's time zone
+	if input.Timezone != "" {
+		if _, err := time.LoadLocation(input.Timezone); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+			return
+		}
+	}
+
 	userID := c.GetUint("user_id")
+	scheduleID, err := h.resolveScheduleID(userID, input.ScheduleID)
+	if err != nil {
+		if errors.Is(err, errScheduleNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Schedule not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create scheduling window"})
+		return
+	}
+
 	window := &models.SchedulingWindow{
 		UserID:    userID,
-		StartHour: input.StartHour,
-		EndHour:   input.EndHour,
-		Weekday:   input.Weekday,
-		IsActive:  true,
+		ScheduleID:  &scheduleID,
+		StartHour:   input.StartHour,
+		StartMinute: input.StartMinute,
+		EndHour:     input.EndHour,
+		EndMinute:   input.EndMinute,
+		Weekday:     input.Weekday,
+		Timezone:    input.Timezone,
+		IsActive:    true,
 	}
 
 	if err := h.db.Create(window).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create scheduling window"})
 		return
 	}
+	h.offerFreedTime(userID)
 
-	c.JSON(http.StatusCreated, gin.H{
-		"id":         window.ID,
-		"start_hour": window.StartHour,
-		"end_hour":   window.EndHour,
-		"weekday":    window.Weekday,
-		"is_active":  window.IsActive,
-	})
+	c.JSON(http.StatusCreated, windowResponse(*window))
 }
 
-// GetAvailableSlots retrieves available time slots for a scheduling link
-func (h *SchedulingHandler) GetAvailableSlots(c *gin.Context) {
-	linkID := c.Param("id")
-	var link models.SchedulingLink
-
-	if err := h.db.First(&link, linkID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
-		return
+// windowResponse formats a scheduling window in snake_case
+func windowResponse(window models.SchedulingWindow) gin.H {
+	return gin.H{
+		"id":           window.ID,
+		"schedule_id":  window.ScheduleID,
+		"start_hour":   window.StartHour,
+		"start_minute": window.StartMinute,
+		"end_hour":     window.EndHour,
+		"end_minute":   window.EndMinute,
+		"weekday":      window.Weekday,
+		"timezone":     window.Timezone,
+		"is_active":    window.IsActive,
 	}
+}
 
-	// Parse date from query param
-	dateStr := c.Query("date")
-	if dateStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing date parameter (expected format: yyyy-mm-dd)"})
-		return
-	}
-	selectedDate, err := time.Parse("2006-01-02", dateStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format (expected: yyyy-mm-dd)"})
-		return
-	}
+// GetTimezone returns the time zone the authenticated user's availability is expressed in
+func (h *SchedulingHandler) GetTimezone(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	var user models.User
 
-	// Check expiration
-	if link.ExpiresAt != nil && selectedDate.After(*link.ExpiresAt) {
-		c.JSON(http.StatusOK, []gin.H{})
+	if err := h.db.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
 
-	// Check max days in advance
-	today := time.Now().Truncate(24 * time.Hour)
-	maxDate := today.AddDate(0, 0, link.MaxDaysInAdvance)
-	if selectedDate.After(maxDate) {
-		c.JSON(http.StatusOK, []gin.H{})
-		return
+	c.JSON(http.StatusOK, gin.H{"timezone": user.Location().String()})
+}
+
+// UpdateTimezone sets the time zone used to interpret the authenticated user's scheduling windows
+func (h *SchedulingHandler) UpdateTimezone(c *gin.Context) {
+	var input struct {
+		Timezone string `json:"timezone" binding:"required"`
 	}
 
-	// Get user's scheduling windows for the selected weekday
-	var windows []models.SchedulingWindow
-	if err := h.db.Where("user_id = ? AND is_active = ? AND weekday = ?", link.UserID, true, int(selectedDate.Weekday())).Find(&windows).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduling windows"})
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if len(windows) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No active scheduling windows found for this day"})
+
+	loc, err := time.LoadLocation(input.Timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
 		return
 	}
 
-	// Get existing meetings for this link on the selected date
-	var meetings []models.Meeting
-	startOfDay := time.Date(selectedDate.Year(), selectedDate.Month(), selectedDate.Day(), 0, 0, 0, 0, selectedDate.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-	if err := h.db.Where("scheduling_link_id = ? AND start_time >= ? AND start_time < ?", link.ID, startOfDay, endOfDay).Find(&meetings).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings"})
+	userID := c.GetUint("user_id")
+	if err := h.db.Model(&models.User{}).Where("id = ?", userID).Update("timezone", loc.String()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update time zone"})
 		return
 	}
+	h.offerFreedTime(userID)
 
-	// Build a list of all possible slots for the day
-	slots := []gin.H{}
-	meetingDuration := time.Duration(link.Duration) * time.Minute
-	for _, window := range windows {
-		windowStart := time.Date(selectedDate.Year(), selectedDate.Month(), selectedDate.Day(), window.StartHour, 0, 0, 0, selectedDate.Location())
-		windowEnd := time.Date(selectedDate.Year(), selectedDate.Month(), selectedDate.Day(), window.EndHour, 0, 0, 0, selectedDate.Location())
-		for slotStart := windowStart; slotStart.Add(meetingDuration).UTC().Before(windowEnd.UTC()) || slotStart.Add(meetingDuration).UTC().Equal(windowEnd.UTC()); slotStart = slotStart.Add(meetingDuration) {
-			slotEnd := slotStart.Add(meetingDuration)
-			// Check for overlap with existing meetings
-			overlaps := false
-			for _, meeting := range meetings {
-				if (slotStart.Before(meeting.EndTime) && slotEnd.After(meeting.StartTime)) || slotStart.Equal(meeting.StartTime) {
-					overlaps = true
-					break
-				}
-			}
-			if !overlaps && slotStart.After(time.Now()) {
-				slots = append(slots, gin.H{
-					"start": slotStart.UTC().Format(time.RFC3339),
-					"end": slotEnd.UTC().Format(time.RFC3339),
-				})
-			}
-		}
-	}
+	c.JSON(http.StatusOK, gin.H{"timezone": loc.String()})
+}
 
-	// If max_uses is set, check if the number of meetings already scheduled meets the limit
-	if link.MaxUses != nil {
-		var totalMeetings int64
-		h.db.Model(&models.Meeting{}).Where("scheduling_link_id = ?", link.ID).Count(&totalMeetings)
-		if int(totalMeetings) >= *link.MaxUses {
-			c.JSON(http.StatusOK, []gin.H{})
-			return
-		}
+// GetAvailableSlots retrieves available time slots for a scheduling link
+func (h *SchedulingHandler) GetAvailableSlots(c *gin.Context) {
+	var link models.SchedulingLink
+
+	// Only the link's own advisor may see which calendars were checked
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&link).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+		return
 	}
 
-	c.JSON(http.StatusOK, slots)
+	h.respondDaySlots(c, link, true)
 }
 
 // GetSchedulingLinks retrieves all scheduling links for the authenticated user
@@ -268,16 +374,7 @@ func (h *SchedulingHandler) GetSchedulingLinks(c *gin.Context) {
 			}
 		}
 
-		response[i] = gin.H{
-			"id":                  link.ID,
-			"title":              link.Title,
-			"duration":           link.Duration,
-			"max_uses":           link.MaxUses,
-			"expires_at":         link.ExpiresAt,
-			"max_days_in_advance": link.MaxDaysInAdvance,
-			"custom_questions":   customQuestions,
-			"is_active":          link.IsActive,
-		}
+		response[i] = linkResponse(link, customQuestions)
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -288,21 +385,46 @@ func (h *SchedulingHandler) GetSchedulingWindows(c *gin.Context) {
 	userID := c.GetUint("user_id")
 	var windows []models.SchedulingWindow
 
-	if err := h.db.Where("user_id = ?", userID).Find(&windows).Error; err != nil {
+	// Optionally limit the windows to one schedule
+	query := h.db.Where("user_id = ?", userID)
+	scheduleID, err := queryScheduleID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule_id"})
+		return
+	}
+	if scheduleID != nil {
+		scope, err := h.scheduleScope(userID, scheduleID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduling windows"})
+			return
+		}
+		query = h.db.Scopes(scope)
+	}
+
+	if err := query.Find(&windows).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduling windows"})
 		return
 	}
 
+	// Upcoming holidays are listed alongside the windows, since no slots are offered on them
+	holidays, err := h.upcomingHolidays(userID, scheduleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch holidays"})
+		return
+	}
+
 	// Convert windows to response format with snake_case
-	response := make([]gin.H, len(windows))
+	windowsResponse := make([]gin.H, len(windows))
 	for i, window := range windows {
-		response[i] = gin.H{
-			"id":         window.ID,
-			"start_hour": window.StartHour,
-			"end_hour":   window.EndHour,
-			"weekday":    window.Weekday,
-			"is_active":  window.IsActive,
-		}
+		windowsResponse[i] = windowResponse(window)
+	}
+	holidaysResponse := make([]gin.H, len(holidays))
+	for i, holiday := range holidays {
+		holidaysResponse[i] = holidayResponse(holiday)
+	}
+	response := gin.H{
+		"windows":  windowsResponse,
+		"holidays": holidaysResponse,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -329,12 +451,12 @@ func (h *SchedulingHandler) DeleteSchedulingWindow(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Scheduling window deleted successfully"})
 }
 
-// GetLinkMeetings retrieves all meetings for a specific scheduling link
+// GetLinkMeetings retrieves all meetings for one of the authenticated user's scheduling links
 func (h *SchedulingHandler) GetLinkMeetings(c *gin.Context) {
 	linkID := c.Param("id")
 	var meetings []models.Meeting
 
-	if err := h.db.Where("scheduling_link_id = ?", linkID).Find(&meetings).Error; err != nil {
+	if err := h.db.Where("scheduling_link_id = ? AND user_id = ?", linkID, c.GetUint("user_id")).Find(&meetings).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings"})
 		return
 	}
@@ -342,52 +464,44 @@ func (h *SchedulingHandler) GetLinkMeetings(c *gin.Context) {
 	// Convert meetings to response format
 	response := make([]gin.H, len(meetings))
 	for i, meeting := range meetings {
-		response[i] = gin.H{
-			"id":            meeting.ID,
-			"client_email":  meeting.ClientEmail,
-			"linkedin_url":  meeting.LinkedInURL,
-			"start_time":    meeting.StartTime,
-			"end_time":      meeting.EndTime,
-			"answers":       meeting.Answers,
-			"context_notes": meeting.ContextNotes,
-		}
+		response[i] = meetingResponse(meeting)
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-// GetPublicSchedulingLink retrieves a scheduling link by ID without requiring authentication
-func (h *SchedulingHandler) GetPublicSchedulingLink(c *gin.Context) {
-	id := c.Param("id")
-	var link models.SchedulingLink
-
-	if err := h.db.First(&link, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
-		return
-	}
-
-	// Check if link is still active
-	if !link.IsActive {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "This scheduling link is no longer active"})
-		return
+// meetingResponse formats a meeting in snake_case for its advisor
+func meetingResponse(meeting models.Meeting) gin.H {
+	return gin.H{
+		"id":                meeting.ID,
+		"link_id":           meeting.SchedulingLinkID,
+		"client_id":         meeting.ClientID,
+		"client_email":      meeting.ClientEmail,
+		"client_name":       meeting.ClientName,
+		"client_phone":      meeting.ClientPhone,
+		"guest_emails":      meeting.GuestEmails,
+		"linkedin_url":      meeting.LinkedInURL,
+		"start_time":        meeting.StartTime,
+		"end_time":          meeting.EndTime,
+		"answers":           meeting.Answers,
+		"context_notes":     meeting.ContextNotes,
+		"status":            meeting.Status,
+		"series_id":         meeting.SeriesID,
+		"cancelled_at":      meeting.CancelledAt,
+		"cancel_reason":     meeting.CancelReason,
+		"rescheduled_at":    meeting.RescheduledAt,
+		"approval_deadline": meeting.ApprovalDeadline,
+		"decline_reason":    meeting.DeclineReason,
 	}
+}
 
-	// Check if link has expired
-	if link.ExpiresAt != nil && time.Now().After(*link.ExpiresAt) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "This scheduling link has expired"})
+// GetPublicSchedulingLink retrieves a scheduling link by ID without requiring authentication
+func (h *SchedulingHandler) GetPublicSchedulingLink(c *gin.Context) {
+	link, ok := h.loadPublicLink(c)
+	if !ok {
 		return
 	}
 
-	// Check max uses
-	if link.MaxUses != nil {
-		var totalMeetings int64
-		h.db.Model(&models.Meeting{}).Where("scheduling_link_id = ?", link.ID).Count(&totalMeetings)
-		if int(totalMeetings) >= *link.MaxUses {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "This scheduling link has reached its maximum number of uses"})
-			return
-		}
-	}
-
 	// Parse custom questions from JSON string
 	var customQuestions []string
 	if link.CustomQuestions != "" {
@@ -405,52 +519,40 @@ func (h *SchedulingHandler) GetPublicSchedulingLink(c *gin.Context) {
 	}
 
 	// Return response in snake_case format
-	c.JSON(http.StatusOK, gin.H{
-		"id":                  link.ID,
-		"title":              link.Title,
-		"duration":           link.Duration,
-		"max_uses":           link.MaxUses,
-		"expires_at":         link.ExpiresAt,
-		"max_days_in_advance": link.MaxDaysInAdvance,
-		"custom_questions":   customQuestions,
-		"is_active":          link.IsActive,
-		"user": gin.H{
-			"name":           user.Name,
-			"profile_picture": user.ProfilePicture,
-		},
-	})
+	response := linkResponse(link, customQuestions)
+	response["user"] = gin.H{
+		"name":            user.Name,
+		"profile_picture": user.ProfilePicture,
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // GetPublicAvailableSlots retrieves available time slots for a scheduling link without requiring authentication
 func (h *SchedulingHandler) GetPublicAvailableSlots(c *gin.Context) {
-	linkID := c.Param("id")
-	var link models.SchedulingLink
-
-	if err := h.db.First(&link, linkID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+	link, ok := h.loadPublicLink(c)
+	if !ok {
 		return
 	}
 
-	// Check if link is still active
-	if !link.IsActive {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "This scheduling link is no longer active"})
-		return
-	}
+	h.respondDaySlots(c, link, false)
+}
 
-	// Check if link has expired
-	if link.ExpiresAt != nil && time.Now().After(*link.ExpiresAt) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "This scheduling link has expired"})
+// respondDaySlots writes the free slots of the link on the day given by the date query parameter,
+// reported in the time zone given by the tz query parameter or else the advisor's. The calendars
+// checked for busy time name the advisor's accounts, so they are only included for the advisor.
+func (h *SchedulingHandler) respondDaySlots(c *gin.Context, link models.SchedulingLink, withSources bool) {
+	// Load the advisor so their windows are interpreted in their time zone
+	var user models.User
+	if err := h.db.First(&user, link.UserID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user information"})
 		return
 	}
+	userLoc := user.Location()
 
-	// Check max uses
-	if link.MaxUses != nil {
-		var totalMeetings int64
-		h.db.Model(&models.Meeting{}).Where("scheduling_link_id = ?", link.ID).Count(&totalMeetings)
-		if int(totalMeetings) >= *link.MaxUses {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "This scheduling link has reached its maximum number of uses"})
-			return
-		}
+	loc, err := requestLocation(c, userLoc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+		return
 	}
 
 	// Parse date from query param
@@ -459,134 +561,645 @@ func (h *SchedulingHandler) GetPublicAvailableSlots(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing date parameter (expected format: yyyy-mm-dd)"})
 		return
 	}
-	selectedDate, err := time.Parse("2006-01-02", dateStr)
+	startOfDay, err := time.ParseInLocation("2006-01-02", dateStr, loc)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format (expected: yyyy-mm-dd)"})
 		return
 	}
 
-	// Check max days in advance
-	today := time.Now().Truncate(24 * time.Hour)
-	maxDate := today.AddDate(0, 0, link.MaxDaysInAdvance)
-	if selectedDate.After(maxDate) {
-		c.JSON(http.StatusOK, []gin.H{})
+	// Days past the link's horizon have no slots, so skip loading calendars for them
+	if beyondHorizon(startOfDay, link, userLoc) {
+		response := gin.H{"timezone": loc.String(), "slots": []gin.H{}}
+		if withSources {
+			response["sources"] = []services.BusySource{}
+		}
+		c.JSON(http.StatusOK, response)
 		return
 	}
 
-	// Get user's scheduling windows for the selected weekday
-	var windows []models.SchedulingWindow
-	if err := h.db.Where("user_id = ? AND is_active = ? AND weekday = ?", link.UserID, true, int(selectedDate.Weekday())).Find(&windows).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduling windows"})
+	// Expiry, max uses and the link's other rules are applied to each slot
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	days, sources, err := h.collectSlots(ctx, link, userLoc, startOfDay, startOfDay)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute available slots"})
 		return
 	}
-	if len(windows) == 0 {
+	if !days[0].HasWindows {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No active scheduling windows found for this day"})
 		return
 	}
 
-	// Get existing meetings for this link on the selected date
-	var meetings []models.Meeting
-	startOfDay := time.Date(selectedDate.Year(), selectedDate.Month(), selectedDate.Day(), 0, 0, 0, 0, selectedDate.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-	if err := h.db.Where("scheduling_link_id = ? AND start_time >= ? AND start_time < ?", link.ID, startOfDay, endOfDay).Find(&meetings).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings"})
+	response := gin.H{
+		"timezone": loc.String(),
+		"slots":    days[0].Slots,
+	}
+	if withSources {
+		response["sources"] = sources
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// maxSlotRangeDays limits how many days a single range query may cover
+const maxSlotRangeDays = 62
+
+// firstAvailableChunkDays is how many days the first-available search loads at a time
+const firstAvailableChunkDays = 14
+
+// GetPublicAvailableSlotsRange retrieves available time slots for every day from the from date
+// to the to date (inclusive) without requiring authentication, grouped by day
+func (h *SchedulingHandler) GetPublicAvailableSlotsRange(c *gin.Context) {
+	link, ok := h.loadPublicLink(c)
+	if !ok {
 		return
 	}
 
-	// Build a list of all possible slots for the day
-	slots := []gin.H{}
-	meetingDuration := time.Duration(link.Duration) * time.Minute
-	for _, window := range windows {
-		windowStart := time.Date(selectedDate.Year(), selectedDate.Month(), selectedDate.Day(), window.StartHour, 0, 0, 0, selectedDate.Location())
-		windowEnd := time.Date(selectedDate.Year(), selectedDate.Month(), selectedDate.Day(), window.EndHour, 0, 0, 0, selectedDate.Location())
-		for slotStart := windowStart; slotStart.Add(meetingDuration).UTC().Before(windowEnd.UTC()) || slotStart.Add(meetingDuration).UTC().Equal(windowEnd.UTC()); slotStart = slotStart.Add(meetingDuration) {
-			slotEnd := slotStart.Add(meetingDuration)
-			// Check for overlap with existing meetings
-			overlaps := false
-			for _, meeting := range meetings {
-				if (slotStart.Before(meeting.EndTime) && slotEnd.After(meeting.StartTime)) || slotStart.Equal(meeting.StartTime) {
-					overlaps = true
-					break
-				}
-			}
-			if !overlaps && slotStart.After(time.Now()) {
-				slots = append(slots, gin.H{
-					"start": slotStart.UTC().Format(time.RFC3339),
-					"end":   slotEnd.UTC().Format(time.RFC3339),
+	// Load the advisor so their windows are interpreted in their time zone
+	var user models.User
+	if err := h.db.First(&user, link.UserID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user information"})
+		return
+	}
+	userLoc := user.Location()
+
+	// Slots are reported in the invitee's time zone, defaulting to the advisor's
+	loc, err := requestLocation(c, userLoc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+		return
+	}
+
+	// Parse the date range from query params
+	fromStr, toStr := c.Query("from"), c.Query("to")
+	if fromStr == "" || toStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing from or to parameter (expected format: yyyy-mm-dd)"})
+		return
+	}
+	from, err := time.ParseInLocation("2006-01-02", fromStr, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date format (expected: yyyy-mm-dd)"})
+		return
+	}
+	to, err := time.ParseInLocation("2006-01-02", toStr, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date format (expected: yyyy-mm-dd)"})
+		return
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The to date must not be before the from date"})
+		return
+	}
+	if to.After(from.AddDate(0, 0, maxSlotRangeDays-1)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A range may cover at most %d days", maxSlotRangeDays)})
+		return
+	}
+
+	// Days past the link's horizon are returned without slots, as the single-day endpoint does
+	lastBookable := to
+	for !lastBookable.Before(from) && beyondHorizon(lastBookable, link, userLoc) {
+		lastBookable = lastBookable.AddDate(0, 0, -1)
+	}
+
+	days := []daySlots{}
+	if !lastBookable.Before(from) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+		days, _, err = h.collectSlots(ctx, link, userLoc, from, lastBookable)
+		if err != nil {
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute available slots"})
+			return
+		}
+	}
+	for day := lastBookable.AddDate(0, 0, 1); !day.After(to); day = day.AddDate(0, 0, 1) {
+		days = append(days, daySlots{Date: day, Slots: []gin.H{}})
+	}
+
+	var firstAvailable interface{}
+	response := make([]gin.H, len(days))
+	for i, day := range days {
+		date := day.Date.Format("2006-01-02")
+		if firstAvailable == nil && len(day.Slots) > 0 {
+			firstAvailable = date
+		}
+		response[i] = gin.H{
+			"date":  date,
+			"slots": day.Slots,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"timezone":        loc.String(),
+		"days":            response,
+		"first_available": firstAvailable,
+	})
+}
+
+// GetPublicFirstAvailableSlots finds the first day with open slots, starting from the from date
+// (default today) and searching up to the link's max days in advance, without requiring authentication
+func (h *SchedulingHandler) GetPublicFirstAvailableSlots(c *gin.Context) {
+	link, ok := h.loadPublicLink(c)
+	if !ok {
+		return
+	}
+
+	// Load the advisor so their windows are interpreted in their time zone
+	var user models.User
+	if err := h.db.First(&user, link.UserID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user information"})
+		return
+	}
+	userLoc := user.Location()
+
+	// Slots are reported in the invitee's time zone, defaulting to the advisor's
+	loc, err := requestLocation(c, userLoc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+		return
+	}
+
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err = time.ParseInLocation("2006-01-02", fromStr, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date format (expected: yyyy-mm-dd)"})
+			return
+		}
+	}
+
+	// Links with a fixed date range can't be booked before it opens, so start searching there
+	firstBookable, _ := newAvailabilityInput(link, userLoc).Rules.BookableDates(time.Now(), userLoc)
+	if opens := time.Date(firstBookable.Year(), firstBookable.Month(), firstBookable.Day(), 0, 0, 0, 0, loc); from.Before(opens) {
+		from = opens
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	// Search in chunks so a far-off first opening doesn't load the whole horizon at once
+	for chunkStart := from; !beyondHorizon(chunkStart, link, userLoc); chunkStart = chunkStart.AddDate(0, 0, firstAvailableChunkDays) {
+		chunkEnd := chunkStart.AddDate(0, 0, firstAvailableChunkDays-1)
+		for chunkEnd.After(chunkStart) && beyondHorizon(chunkEnd, link, userLoc) {
+			chunkEnd = chunkEnd.AddDate(0, 0, -1)
+		}
+
+		days, _, err := h.collectSlots(ctx, link, userLoc, chunkStart, chunkEnd)
+		if err != nil {
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute available slots"})
+			return
+		}
+
+		for _, day := range days {
+			if len(day.Slots) > 0 {
+				c.JSON(http.StatusOK, gin.H{
+					"timezone": loc.String(),
+					"date":     day.Date.Format("2006-01-02"),
+					"slots":    day.Slots,
 				})
+				return
 			}
 		}
 	}
 
-	c.JSON(http.StatusOK, slots)
+	c.JSON(http.StatusOK, gin.H{
+		"timezone": loc.String(),
+		"date":     nil,
+		"slots":    []gin.H{},
+	})
 }
 
-// CreatePublicMeeting creates a new meeting without requiring authentication
-func (h *SchedulingHandler) CreatePublicMeeting(c *gin.Context) {
-	linkID := c.Param("id")
+// loadPublicLink fetches the link named in the URL and checks that it can still be booked publicly.
+// It writes the error response and returns false if the link is missing, inactive, expired or used up.
+func (h *SchedulingHandler) loadPublicLink(c *gin.Context) (models.SchedulingLink, bool) {
 	var link models.SchedulingLink
-
-	// Get the scheduling link
-	if err := h.db.First(&link, linkID).Error; err != nil {
+	if err := h.db.First(&link, c.Param("id")).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
-		return
+		return link, false
 	}
 
 	// Check if link is still active
 	if !link.IsActive {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "This scheduling link is no longer active"})
-		return
+		return link, false
 	}
 
 	// Check if link has expired
 	if link.ExpiresAt != nil && time.Now().After(*link.ExpiresAt) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "This scheduling link has expired"})
-		return
+		return link, false
 	}
 
 	// Check max uses
 	if link.MaxUses != nil {
 		var totalMeetings int64
-		h.db.Model(&models.Meeting{}).Where("scheduling_link_id = ?", link.ID).Count(&totalMeetings)
+		h.db.Model(&models.Meeting{}).Where("scheduling_link_id = ? AND status IN ?", link.ID, models.LiveMeetingStatuses).Count(&totalMeetings)
 		if int(totalMeetings) >= *link.MaxUses {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "This scheduling link has reached its maximum number of uses"})
-			return
+			return link, false
 		}
 	}
 
+	return link, true
+}
+
+// requestLocation returns the time zone named by the tz query parameter, or fallback if none is given
+func requestLocation(c *gin.Context, fallback *time.Location) (*time.Location, error) {
+	tz := c.Query("tz")
+	if tz == "" {
+		return fallback, nil
+	}
+	return time.LoadLocation(tz)
+}
+
+// daySlots holds the free slots of one day in the requested time zone
+type daySlots struct {
+	Date       time.Time
+	HasWindows bool
+	Slots      []gin.H
+}
+
+// collectSlots computes the free slots of every day from firstDay to lastDay inclusive, given as
+// midnights in the requested time zone. Windows, overrides, meetings and calendar busy time are
+// loaded once for the whole range.
+func (h *SchedulingHandler) collectSlots(ctx context.Context, link models.SchedulingLink, userLoc *time.Location, firstDay, lastDay time.Time) ([]daySlots, []services.BusySource, error) {
+	in, sources, err := h.loadAvailability(ctx, link, userLoc, firstDay, lastDay.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	days := []daySlots{}
+	loc := firstDay.Location()
+	for day := firstDay; !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		result := in.Day(day)
+		slots := make([]gin.H, len(result.Slots))
+		for i, slot := range result.Slots {
+			slots[i] = gin.H{
+				"start":      slot.Start.In(loc).Format(time.RFC3339),
+				"end":        slot.End.In(loc).Format(time.RFC3339),
+				"seats_left": slot.SeatsLeft,
+			}
+			// Ranked links score every slot so booking pages can highlight the recommended times
+			if in.Rules.Ranked() {
+				slots[i]["score"] = slot.Score
+				slots[i]["recommended"] = slot.Recommended
+			}
+		}
+		days = append(days, daySlots{Date: day, HasWindows: result.HasWindows, Slots: slots})
+	}
+
+	return days, sources, nil
+}
+
+// loadAvailability loads the advisor's windows, overrides, meetings and calendar busy time needed
+// to check slots of the link between rangeStart and rangeEnd
+func (h *SchedulingHandler) loadAvailability(ctx context.Context, link models.SchedulingLink, userLoc *time.Location, rangeStart, rangeEnd time.Time) (availability.Input, []services.BusySource, error) {
+	in := newAvailabilityInput(link, userLoc)
+
+	// Windows and overrides come from the link's schedule
+	scope, err := h.scheduleScope(link.UserID, link.ScheduleID)
+	if err != nil {
+		return in, nil, fmt.Errorf("failed to resolve schedule: %v", err)
+	}
+
+	// Get the schedule's windows
+	var windows []models.SchedulingWindow
+	if err := h.db.Scopes(scope).Where("is_active = ?", true).Find(&windows).Error; err != nil {
+		return in, nil, fmt.Errorf("failed to fetch scheduling windows: %v", err)
+	}
+	for _, window := range windows {
+		in.Windows = append(in.Windows, availability.Window{
+			Weekday:     time.Weekday(window.Weekday),
+			StartMinute: window.StartHour*60 + window.StartMinute,
+			EndMinute:   window.EndHour*60 + window.EndMinute,
+			Location:    window.Location(nil),
+		})
+	}
+
+	// Get date overrides for the advisor's dates touched by the range
+	var overrides []models.SchedulingOverride
+	if err := h.db.Scopes(scope).Where(
+		"start_date <= ? AND end_date >= ?",
+		rangeEnd.In(userLoc).Format("2006-01-02"), rangeStart.In(userLoc).Format("2006-01-02"),
+	).Find(&overrides).Error; err != nil {
+		return in, nil, fmt.Errorf("failed to fetch scheduling overrides: %v", err)
+	}
+	for _, override := range overrides {
+		in.Overrides = append(in.Overrides, availability.Override{
+			StartDate:   override.StartDate,
+			EndDate:     override.EndDate,
+			StartMinute: override.StartMinute,
+			EndMinute:   override.EndMinute,
+			Available:   override.IsAvailable,
+		})
+	}
+
+	// Imported holidays block whole days like unavailable overrides
+	holidayScope, err := h.holidayScope(link.UserID, link.ScheduleID)
+	if err != nil {
+		return in, nil, fmt.Errorf("failed to resolve holidays: %v", err)
+	}
+	var holidays []models.Holiday
+	if err := h.db.Scopes(holidayScope).Where(
+		"start_date <= ? AND end_date >= ?",
+		rangeEnd.In(userLoc).Format("2006-01-02"), rangeStart.In(userLoc).Format("2006-01-02"),
+	).Find(&holidays).Error; err != nil {
+		return in, nil, fmt.Errorf("failed to fetch holidays: %v", err)
+	}
+	for _, holiday := range holidays {
+		in.Overrides = append(in.Overrides, availability.Override{
+			StartDate: holiday.StartDate,
+			EndDate:   holiday.EndDate,
+			Holiday:   holiday.Name,
+		})
+	}
+
+	if _, err := loadBookings(h.db, link, &in, rangeStart, rangeEnd, ""); err != nil {
+		return in, nil, err
+	}
+
+	// Get busy time from the advisor's connected calendars
+	busy, sources, err := h.calendarService.GetBusyIntervals(ctx, link.UserID, rangeStart, rangeEnd)
+	if err != nil {
+		return in, nil, err
+	}
+	for _, interval := range busy {
+		in.Busy = append(in.Busy, availability.Interval{Start: interval.Start, End: interval.End})
+	}
+
+	return in, sources, nil
+}
+
+// loadBookings loads all of the advisor's meetings, whichever link booked them, over the whole weeks
+// from rangeStart to rangeEnd so weekly caps can be counted, and counts the link's uses if it is
+// limited. Active slot holds other than exceptHold are loaded as held meetings. Bookings pass their
+// transaction so the check sees what was committed before the lock.
+func loadBookings(db *gorm.DB, link models.SchedulingLink, in *availability.Input, rangeStart, rangeEnd time.Time, exceptHold string) ([]models.Meeting, error) {
+	var meetings []models.Meeting
+	meetingsStart := availability.StartOfWeek(rangeStart, in.Location)
+	meetingsEnd := availability.StartOfWeek(rangeEnd, in.Location).AddDate(0, 0, 7)
+	if err := db.Where(
+		"user_id = ? AND status IN ? AND start_time < ? AND end_time > ?",
+		link.UserID, models.LiveMeetingStatuses, meetingsEnd, meetingsStart,
+	).Find(&meetings).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch meetings: %v", err)
+	}
+	in.Meetings = availabilityMeetings(meetings)
+
+	// Held slots are hidden from everyone but the invitee holding them
+	var holds []models.SlotHold
+	if err := db.Where(
+		"user_id = ? AND token <> ? AND expires_at > ? AND start_time < ? AND end_time > ?",
+		link.UserID, exceptHold, time.Now(), meetingsEnd, meetingsStart,
+	).Find(&holds).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch slot holds: %v", err)
+	}
+	for _, hold := range holds {
+		in.Meetings = append(in.Meetings, availability.Meeting{
+			Start:  hold.StartTime,
+			End:    hold.EndTime,
+			LinkID: hold.SchedulingLinkID,
+			Held:   true,
+		})
+	}
+
+	// Count the link's meetings and holds if it has a limit on uses
+	if link.MaxUses != nil {
+		var totalMeetings, totalHolds int64
+		if err := db.Model(&models.Meeting{}).Where("scheduling_link_id = ? AND status IN ?", link.ID, models.LiveMeetingStatuses).Count(&totalMeetings).Error; err != nil {
+			return nil, fmt.Errorf("failed to count meetings: %v", err)
+		}
+		if err := db.Model(&models.SlotHold{}).Where("scheduling_link_id = ? AND token <> ? AND expires_at > ?", link.ID, exceptHold, time.Now()).Count(&totalHolds).Error; err != nil {
+			return nil, fmt.Errorf("failed to count slot holds: %v", err)
+		}
+		in.Uses = int(totalMeetings + totalHolds)
+	}
+	return meetings, nil
+}
+
+// lockAdvisor locks the advisor's row until the end of the transaction, so bookings through any of
+// their links are checked and saved one at a time and two invitees can't both take the last slot
+func lockAdvisor(tx *gorm.DB, userID uint) (models.User, error) {
+	var user models.User
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error
+	return user, err
+}
+
+// newAvailabilityInput converts a link's settings into availability rules, evaluated as of now
+func newAvailabilityInput(link models.SchedulingLink, userLoc *time.Location) availability.Input {
+	return availability.Input{
+		Location: userLoc,
+		Now:      time.Now(),
+		Rules: availability.Rules{
+			LinkID:           link.ID,
+			Inactive:         !link.IsActive,
+			Duration:         time.Duration(link.Duration) * time.Minute,
+			Seats:            link.Seats,
+			Interval:         time.Duration(link.SlotInterval) * time.Minute,
+			TopOfHour:        link.TopOfHourOnly,
+			BufferBefore:     time.Duration(link.BufferBefore) * time.Minute,
+			BufferAfter:      time.Duration(link.BufferAfter) * time.Minute,
+			MinNotice:        time.Duration(link.MinNotice) * time.Minute,
+			Ranking:          link.SlotRanking,
+			Horizon:          link.HorizonMode,
+			MaxDaysInAdvance: link.MaxDaysInAdvance,
+			StartDate:        stringValue(link.StartDate),
+			EndDate:          stringValue(link.EndDate),
+			ExpiresAt:        link.ExpiresAt,
+			MaxUses:          link.MaxUses,
+			MaxPerDay:        link.MaxPerDay,
+			MaxPerWeek:       link.MaxPerWeek,
+		},
+	}
+}
+
+// stringValue returns the string a pointer refers to, or an empty string for nil
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// availabilityMeetings converts meetings into the bookings the availability engine checks against
+func availabilityMeetings(meetings []models.Meeting) []availability.Meeting {
+	result := make([]availability.Meeting, len(meetings))
+	for i, meeting := range meetings {
+		result[i] = availability.Meeting{
+			Start:  meeting.StartTime,
+			End:    meeting.EndTime,
+			LinkID: meeting.SchedulingLinkID,
+		}
+	}
+	return result
+}
+
+// beyondHorizon reports whether a day lies past the last date the link can be booked for, which
+// depends on the link's horizon mode and is counted in the advisor's time zone
+func beyondHorizon(day time.Time, link models.SchedulingLink, userLoc *time.Location) bool {
+	_, maxDate := newAvailabilityInput(link, userLoc).Rules.BookableDates(time.Now(), userLoc)
+	selected := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, userLoc)
+	return selected.After(maxDate)
+}
+
+// ExplainSlot reports whether a meeting starting at the start query parameter (RFC3339) can be
+// booked through one of the authenticated user's links and, if not, which rule excludes it
+func (h *SchedulingHandler) ExplainSlot(c *gin.Context) {
+	var link models.SchedulingLink
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&link).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, link.UserID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user information"})
+		return
+	}
+	userLoc := user.Location()
+
+	// Times are reported in the requested time zone, defaulting to the advisor's
+	loc, err := requestLocation(c, userLoc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time zone"})
+		return
+	}
+
+	startStr := c.Query("start")
+	if startStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing start parameter (expected format: RFC3339)"})
+		return
+	}
+	start, err := time.Parse(time.RFC3339, startStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start format (expected: RFC3339)"})
+		return
+	}
+
+	// Load the advisor's day around the slot, plus a day either side to leave room for the buffers
+	day := availability.StartOfDay(start, userLoc)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	in, sources, err := h.loadAvailability(ctx, link, userLoc, day.AddDate(0, 0, -1), day.AddDate(0, 0, 2))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute availability"})
+		return
+	}
+
+	explanation := in.Explain(start)
+	c.JSON(http.StatusOK, gin.H{
+		"timezone":   loc.String(),
+		"start":      explanation.Slot.Start.In(loc).Format(time.RFC3339),
+		"end":        explanation.Slot.End.In(loc).Format(time.RFC3339),
+		"available":  explanation.Available,
+		"reason":     explanation.Reason,
+		"detail":     explanation.Detail,
+		"seats_left": explanation.SeatsLeft,
+		"sources":    sources,
+	})
+}
+
+// reasonInvalidDuration rejects a booking whose end time doesn't match the link's duration. The
+// availability rules only see start times, so the handler checks this itself.
+const reasonInvalidDuration availability.Reason = "invalid_duration"
+
+// reasonInvalidHold rejects a booking whose hold token doesn't hold the slot being booked, because
+// the hold expired, belongs to another link or is for another time
+const reasonInvalidHold availability.Reason = "invalid_hold"
+
+// bookingError describes to the invitee why a time they picked cannot be booked
+func bookingError(reason availability.Reason) string {
+	switch reason {
+	case availability.ReasonInPast, availability.ReasonMinNotice:
+		return "This time slot is too soon to book"
+	case availability.ReasonMeetingConflict, availability.ReasonHeld, availability.ReasonBusyCalendar, availability.ReasonBuffer:
+		return "This time slot is no longer available"
+	case availability.ReasonFullyBooked:
+		return "This session is fully booked"
+	case availability.ReasonDailyCap:
+		return "No more meetings can be booked on this day"
+	case availability.ReasonWeeklyCap:
+		return "No more meetings can be booked in this week"
+	case availability.ReasonMaxUsesReached:
+		return "This scheduling link has reached its maximum number of uses"
+	case availability.ReasonNotRecommended:
+		return "This time slot is not offered, please pick one of the suggested times"
+	case availability.ReasonBeyondHorizon, availability.ReasonOutsideDateRange:
+		return "This date is outside the link's booking period"
+	default:
+		return "This time slot cannot be booked"
+	}
+}
+
+// CreatePublicMeeting creates a new meeting without requiring authentication. The check and insert
+// run in one transaction holding the advisor's lock, and a request sent with an Idempotency-Key
+// header returns the original booking when retried.
+func (h *SchedulingHandler) CreatePublicMeeting(c *gin.Context) {
+	// A retry of a booking that used up the link must still get its result, so look for one first
+	idempotency, ok := readIdempotency(c, "meeting:"+c.Param("id"))
+	if !ok {
+		return
+	}
+	if result, err := idempotency.replay(h.db); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create meeting"})
+		return
+	} else if result != nil {
+		c.JSON(result.status, result.body)
+		return
+	}
+
+	// Get the scheduling link
+	link, ok := h.loadPublicLink(c)
+	if !ok {
+		return
+	}
+
 	var input struct {
-		ClientEmail  string            `json:"client_email" binding:"required,email"`
-		LinkedInURL  string            `json:"linkedin_url"`
-		StartTime    time.Time         `json:"start_time" binding:"required"`
-		EndTime      time.Time         `json:"end_time" binding:"required"`
-		Answers      map[string]string `json:"answers" binding:"required"`
+		bookingInvitee
+		StartTime time.Time         `json:"start_time" binding:"required"`
+		EndTime   time.Time         `json:"end_time" binding:"required"`
+		Answers   map[string]string `json:"answers" binding:"required"`
+		HoldToken string            `json:"hold_token"` // the invitee's hold on the slot, if they placed one
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	guests, err := input.guestList(link)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	// Validate time slot
-	if input.StartTime.After(input.EndTime) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time slot"})
+	// Only slots of the link's length are offered
+	if !input.EndTime.Equal(input.StartTime.Add(time.Duration(link.Duration) * time.Minute)) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  fmt.Sprintf("Meetings booked through this link last %d minutes", link.Duration),
+			"reason": reasonInvalidDuration,
+		})
 		return
 	}
 
-	// Check if the time slot is still available
-	var existingMeeting models.Meeting
-	result := h.db.Where(
-		"scheduling_link_id = ? AND ((start_time <= ? AND end_time > ?) OR (start_time < ? AND end_time >= ?))",
-		link.ID, input.StartTime, input.StartTime, input.EndTime, input.EndTime,
-	).First(&existingMeeting)
+	var user models.User
+	if err := h.db.First(&user, link.UserID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user information"})
+		return
+	}
 
-	if result.Error != nil {
-		if result.Error != gorm.ErrRecordNotFound {
-			// Only log actual errors, not the "no record found" case
-			c.Error(fmt.Errorf("error checking time slot availability: %v", result.Error))
-		}
-	} else {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "This time slot is no longer available"})
+	// Load the advisor's day around the slot, plus a day either side to leave room for the buffers.
+	// Calendar busy time is fetched before taking the lock, so slow calendar APIs don't hold up other
+	// bookings
+	day := availability.StartOfDay(input.StartTime, user.Location())
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	in, _, err := h.loadAvailability(ctx, link, user.Location(), day.AddDate(0, 0, -1), day.AddDate(0, 0, 2))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check time slot availability"})
 		return
 	}
 
@@ -596,71 +1209,199 @@ func (h *SchedulingHandler) CreatePublicMeeting(c *gin.Context) {
 		answers = append(answers, question+": "+answer)
 	}
 
-	// Create the meeting
+	// Links that require approval book the slot as a pending request, which holds it until the
+	// advisor accepts or declines it or the request expires
+	deadline := approvalDeadline(link, input.StartTime)
 	meeting := &models.Meeting{
 		SchedulingLinkID: link.ID,
 		UserID:          link.UserID,
 		ClientEmail:     input.ClientEmail,
+		ClientName:      input.ClientName,
+		ClientPhone:     input.ClientPhone,
+		GuestEmails:     guests,
 		LinkedInURL:     input.LinkedInURL,
 		StartTime:       input.StartTime,
 		EndTime:         input.EndTime,
 		Answers:         answers,
 		LinkedInData:    "{}", // Initialize with empty JSON object
+		Status:          meetingStatus(deadline),
+		ApprovalDeadline: deadline,
 	}
+	var result *bookingResult
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the advisor so bookings through any of their links are checked one at a time
+		if _, err := lockAdvisor(tx, link.UserID); err != nil {
+			return err
+		}
+
+		// A retry that waited for the lock gets the result of the request it waited for
+		var err error
+		if result, err = idempotency.replay(tx); err != nil || result != nil {
+			return err
+		}
+
+		// A hold only lets its own slot be booked past it
+		if input.HoldToken != "" {
+			var hold models.SlotHold
+			err := tx.Where("token = ? AND scheduling_link_id = ?", input.HoldToken, link.ID).First(&hold).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if err != nil || !hold.StartTime.Equal(input.StartTime) || !hold.ExpiresAt.After(time.Now()) {
+				result = &bookingResult{status: http.StatusBadRequest, body: gin.H{
+					"error":  "Your hold on this time slot has expired, please pick a time again",
+					"reason": reasonInvalidHold,
+				}}
+				return nil
+			}
+		}
 
-	if err := h.db.Create(meeting).Error; err != nil {
+		// Meetings, holds and uses may have changed since availability was loaded
+		meetings, err := loadBookings(tx, link, &in, day.AddDate(0, 0, -1), day.AddDate(0, 0, 2), input.HoldToken)
+		if err != nil {
+			return err
+		}
+		in.Now = time.Now()
+
+		// Apply every rule the slots endpoint does, so only a slot that would have been offered can
+		// be booked
+		if explanation := in.Explain(input.StartTime); !explanation.Available {
+			result = &bookingResult{status: http.StatusBadRequest, body: gin.H{
+				"error":  bookingError(explanation.Reason),
+				"reason": explanation.Reason,
+			}}
+			return nil
+		}
+
+		// The same invitee can only take one seat of a group session
+		for _, other := range meetings {
+			if link.Seats > 1 && other.SchedulingLinkID == link.ID && other.StartTime.Equal(input.StartTime) &&
+				strings.EqualFold(other.ClientEmail, input.ClientEmail) {
+				result = &bookingResult{status: http.StatusBadRequest, body: gin.H{"error": "You have already booked this session"}}
+				return nil
+			}
+		}
+
+		// Create the meeting, which takes the place of the invitee's hold
+		client, err := findOrCreateClient(tx, link.UserID, input.bookingInvitee)
+		if err != nil {
+			return err
+		}
+		meeting.ClientID = &client.ID
+		if err := tx.Create(meeting).Error; err != nil {
+			return err
+		}
+		if err := recordMeetingsBooked(tx, models.MeetingActorInvitee, *meeting); err != nil {
+			return err
+		}
+		if input.HoldToken != "" {
+			if err := tx.Unscoped().Where("token = ? AND scheduling_link_id = ?", input.HoldToken, link.ID).Delete(&models.SlotHold{}).Error; err != nil {
+				return err
+			}
+			// The hold may have been a slot offered from the waitlist
+			if err := tx.Model(&models.WaitlistEntry{}).Where("hold_token = ? AND status = ?", input.HoldToken, models.WaitlistStatusOffered).
+				Update("status", models.WaitlistStatusClaimed).Error; err != nil {
+				return err
+			}
+		}
+
+		response := gin.H{
+			"id":                meeting.ID,
+			"client_email":      meeting.ClientEmail,
+			"client_name":       meeting.ClientName,
+			"client_phone":      meeting.ClientPhone,
+			"guest_emails":      meeting.GuestEmails,
+			"linkedin_url":      meeting.LinkedInURL,
+			"start_time":        meeting.StartTime,
+			"end_time":          meeting.EndTime,
+			"answers":           input.Answers,
+			"status":            meeting.Status,
+			"approval_deadline": meeting.ApprovalDeadline,
+		}
+		// The invitee uses the manage token to cancel or reschedule without an account
+		token, err := manageToken(*meeting)
+		if err != nil {
+			return err
+		}
+		response["manage_token"] = token
+
+		result = &bookingResult{status: http.StatusCreated, body: response}
+		return idempotency.save(tx, *result)
+	})
+	if err != nil {
+		c.Error(err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create meeting"})
 		return
 	}
+	if result.status != http.StatusCreated || meeting.ID == 0 {
+		c.JSON(result.status, result.body)
+		return
+	}
 
-	// Find and deactivate the scheduling window that contains this meeting
-	var window models.SchedulingWindow
-	meetingWeekday := int(input.StartTime.Weekday())
-	meetingHour := input.StartTime.Hour()
-	if err := h.db.Where(
-		"user_id = ? AND weekday = ? AND start_hour <= ? AND end_hour > ? AND is_active = ?",
-		link.UserID, meetingWeekday, meetingHour, meetingHour, true,
-	).First(&window).Error; err == nil {
-		// Update the window's is_active status
-		if err := h.db.Model(&window).Update("is_active", false).Error; err != nil {
+	// Send email notification in a goroutine
+	meetingDetails := participantDetails(map[string]interface{}{
+		"meeting_id":   meeting.ID,
+		"linkedin_url": input.LinkedInURL,
+		"start_time":   input.StartTime.Format(time.RFC3339),
+		"end_time":     input.EndTime.Format(time.RFC3339),
+		"answers":      answers,
+	}, *meeting)
+	if deadline != nil {
+		meetingDetails["approval_deadline"] = deadline.Format(time.RFC3339)
+	}
+	h.notifyNewMeeting(user.Email, meetingDetails)
+	h.notifyBookingConfirmation([]models.Meeting{*meeting}, link, user, "")
+
+	c.JSON(result.status, result.body)
+}
+
+// notifyNewMeeting emails the advisor about a new booking in the background. Nothing is sent if the
+// handler has no email service.
+func (h *SchedulingHandler) notifyNewMeeting(toEmail string, meetingDetails map[string]interface{}) {
+	if h.emailService == nil {
+		return
+	}
+	go func() {
+		// Create a new context with timeout for the entire email process
+		emailCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		if err := h.emailService.SendMeetingNotification(emailCtx, toEmail, meetingDetails); err != nil {
 			// Log the error but don't fail the meeting creation
-			c.Error(fmt.Errorf("failed to update scheduling window: %v", err))
+			fmt.Printf("Failed to send email notification: %v\n", err)
 		}
+	}()
+}
+
+// notifyBookingConfirmation emails the invitee and their guests a confirmation of their booking in
+// the background, with the meetings attached as a calendar file. Nothing is sent if the handler has
+// no email service.
+func (h *SchedulingHandler) notifyBookingConfirmation(meetings []models.Meeting, link models.SchedulingLink, advisor models.User, recurrence string) {
+	if h.emailService == nil || len(meetings) == 0 {
+		return
 	}
 
-	// Get the user's email to send notification
-	var user models.User
-	if err := h.db.First(&user, link.UserID).Error; err != nil {
-		// Log the error but don't fail the meeting creation
-		c.Error(fmt.Errorf("failed to fetch user for email notification: %v", err))
-	} else {
-		// Send email notification in a goroutine
-		go func() {
-			// Create a new context with timeout for the entire email process
-			emailCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-			defer cancel()
-
-			meetingDetails := map[string]interface{}{
-				"meeting_id":   meeting.ID,
-				"client_email": input.ClientEmail,
-				"linkedin_url": input.LinkedInURL,
-				"start_time":   input.StartTime.Format(time.RFC3339),
-				"end_time":     input.EndTime.Format(time.RFC3339),
-				"answers":      answers,
-			}
-			if err := h.emailService.SendMeetingNotification(emailCtx, user.Email, meetingDetails); err != nil {
+	first := meetings[0]
+	details := participantDetails(map[string]interface{}{
+		"title":        link.Title,
+		"advisor_name": advisor.Name,
+		"start_time":   first.StartTime.Format(time.RFC3339),
+		"end_time":     first.EndTime.Format(time.RFC3339),
+		"recurrence":   recurrence,
+	}, first)
+	if first.ApprovalDeadline != nil {
+		details["approval_deadline"] = first.ApprovalDeadline.Format(time.RFC3339)
+	}
+	ics := meetingsICS(meetings, link, advisor, 0, time.Now())
+	go func() {
+		emailCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		for _, email := range meetingParticipants(first) {
+			if err := h.emailService.SendBookingConfirmation(emailCtx, email, details, ics); err != nil {
 				// Log the error but don't fail the meeting creation
 				fmt.Printf("Failed to send email notification: %v\n", err)
 			}
-		}()
-	}
-
-	c.JSON(http.StatusCreated, gin.H{
-		"id":            meeting.ID,
-		"client_email":  meeting.ClientEmail,
-		"linkedin_url":  meeting.LinkedInURL,
-		"start_time":    meeting.StartTime,
-		"end_time":      meeting.EndTime,
-		"answers":       input.Answers,
-	})
+		}
+	}()
 }
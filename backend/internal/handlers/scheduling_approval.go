@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+	"gorm.io/gorm"
+)
+
+// defaultApprovalHours is how long the advisor has to accept a pending booking when the link
+// doesn't say
+const defaultApprovalHours = 24
+
+// approvalDeadline returns when a booking starting at start expires unless the advisor accepts it,
+// or nil if the link doesn't require approval. Requests expire when the meeting would start at the latest.
+func approvalDeadline(link models.SchedulingLink, start time.Time) *time.Time {
+	if !link.RequiresApproval {
+		return nil
+	}
+	deadline := time.Now().Add(time.Duration(link.ApprovalHours) * time.Hour)
+	if start.Before(deadline) {
+		deadline = start
+	}
+	return &deadline
+}
+
+// meetingStatus returns the status of a new booking with the given approval deadline
+func meetingStatus(deadline *time.Time) string {
+	if deadline != nil {
+		return models.MeetingStatusPending
+	}
+	return models.MeetingStatusScheduled
+}
+
+// decidePendingRequest applies the advisor's decision to the pending booking request a meeting
+// belongs to: the meeting itself, or every pending occurrence of its series, as a series is
+// requested in one submission. It returns how many meetings were changed.
+func decidePendingRequest(db *gorm.DB, meeting models.Meeting, change meetingChange) (int, error) {
+	decided := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("status = ? AND approval_deadline > ?", models.MeetingStatusPending, time.Now())
+		if meeting.SeriesID != nil {
+			query = query.Where("series_id = ?", *meeting.SeriesID)
+		} else {
+			query = query.Where("id = ?", meeting.ID)
+		}
+		var pending []models.Meeting
+		if err := query.Find(&pending).Error; err != nil {
+			return err
+		}
+
+		for i := range pending {
+			if err := changeMeetingStatus(tx, &pending[i], change); err != nil {
+				return err
+			}
+			decided++
+		}
+		return nil
+	})
+	return decided, err
+}
+
+// notifyInviteeDecision emails the invitee and their guests that the booking request was accepted,
+// declined or expired. Nothing is sent if the handler has no email service.
+func (h *SchedulingHandler) notifyInviteeDecision(meeting models.Meeting, outcome string, reason string) {
+	if h.emailService == nil {
+		return
+	}
+
+	var link models.SchedulingLink
+	if err := h.db.First(&link, meeting.SchedulingLinkID).Error; err != nil {
+		fmt.Printf("Failed to fetch scheduling link for email notification: %v\n", err)
+		return
+	}
+
+	details := map[string]interface{}{
+		"title":      link.Title,
+		"start_time": meeting.StartTime.Format(time.RFC3339),
+		"end_time":   meeting.EndTime.Format(time.RFC3339),
+		"series":     meeting.SeriesID != nil,
+		"reason":     reason,
+	}
+	go func() {
+		emailCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		for _, email := range meetingParticipants(meeting) {
+			if err := h.emailService.SendBookingRequestOutcome(emailCtx, email, outcome, details); err != nil {
+				// Log the error, the decision itself has been saved
+				fmt.Printf("Failed to send email notification: %v\n", err)
+			}
+		}
+	}()
+}
+
+// AcceptMeeting confirms a pending booking request, together with the rest of its series if it
+// belongs to one, and tells the invitee
+func (h *SchedulingHandler) AcceptMeeting(c *gin.Context) {
+	meeting, ok := h.loadOwnMeeting(c)
+	if !ok {
+		return
+	}
+
+	if meeting.Status != models.MeetingStatusPending {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only pending booking requests can be accepted"})
+		return
+	}
+	now := time.Now()
+	if meeting.ApprovalDeadline != nil && !meeting.ApprovalDeadline.After(now) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This booking request has expired"})
+		return
+	}
+
+	accepted, err := decidePendingRequest(h.db, meeting, meetingChange{
+		to:      models.MeetingStatusScheduled,
+		event:   models.MeetingEventAccepted,
+		actor:   models.MeetingActorAdvisor,
+		actorID: advisorActor(c),
+		updates: map[string]interface{}{"approval_deadline": nil},
+	})
+	if err != nil {
+		respondStatusChangeError(c, err, "Failed to accept meeting")
+		return
+	}
+	// Expired or declined in the meantime
+	if accepted == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "This booking request is no longer pending"})
+		return
+	}
+
+	meeting.Status = models.MeetingStatusScheduled
+	meeting.ApprovalDeadline = nil
+	h.notifyInviteeDecision(meeting, "accepted", "")
+
+	c.JSON(http.StatusOK, meetingResponse(meeting))
+}
+
+// DeclineMeeting turns down a pending booking request with an optional reason, together with the
+// rest of its series if it belongs to one, releasing the time and telling the invitee
+func (h *SchedulingHandler) DeclineMeeting(c *gin.Context) {
+	meeting, ok := h.loadOwnMeeting(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Reason string `json:"reason" binding:"max=1000"`
+	}
+	// The body is optional, as the reason is
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if meeting.Status != models.MeetingStatusPending {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only pending booking requests can be declined"})
+		return
+	}
+
+	declined, err := decidePendingRequest(h.db, meeting, meetingChange{
+		to:      models.MeetingStatusDeclined,
+		event:   models.MeetingEventDeclined,
+		actor:   models.MeetingActorAdvisor,
+		actorID: advisorActor(c),
+		reason:  input.Reason,
+		updates: map[string]interface{}{"approval_deadline": nil, "decline_reason": input.Reason},
+	})
+	if err != nil {
+		respondStatusChangeError(c, err, "Failed to decline meeting")
+		return
+	}
+	if declined == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "This booking request is no longer pending"})
+		return
+	}
+
+	meeting.Status = models.MeetingStatusDeclined
+	meeting.ApprovalDeadline = nil
+	meeting.DeclineReason = input.Reason
+	h.notifyInviteeDecision(meeting, "declined", input.Reason)
+	h.offerFreedTime(meeting.UserID)
+
+	c.JSON(http.StatusOK, meetingResponse(meeting))
+}
+
+// ExpirePendingMeetings expires the booking requests whose approval deadline has passed, releasing
+// their time, and tells the invitees. It is run periodically by the server.
+func (h *SchedulingHandler) ExpirePendingMeetings() error {
+	now := time.Now()
+	var pending []models.Meeting
+	if err := h.db.Where("status = ? AND approval_deadline <= ?", models.MeetingStatusPending, now).Find(&pending).Error; err != nil {
+		return fmt.Errorf("failed to fetch pending meetings: %v", err)
+	}
+
+	notifiedSeries := map[uint]bool{}
+	freedUsers := map[uint]bool{}
+	for _, meeting := range pending {
+		err := changeMeetingStatus(h.db, &meeting, meetingChange{
+			to:    models.MeetingStatusExpired,
+			event: models.MeetingEventExpired,
+			actor: models.MeetingActorSystem,
+		})
+		// Accepted or declined since it was fetched
+		if errors.Is(err, errMeetingChanged) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to expire meeting %d: %v", meeting.ID, err)
+		}
+		freedUsers[meeting.UserID] = true
+
+		// A series was requested together, so its invitee hears about it once
+		if meeting.SeriesID != nil {
+			if notifiedSeries[*meeting.SeriesID] {
+				continue
+			}
+			notifiedSeries[*meeting.SeriesID] = true
+		}
+		h.notifyInviteeDecision(meeting, "expired", "")
+	}
+
+	for userID := range freedUsers {
+		h.offerFreedTime(userID)
+	}
+	return nil
+}
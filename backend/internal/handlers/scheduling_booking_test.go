@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+	"github.com/yourusername/advisor-scheduling/internal/services"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// bookingTestDB connects to the MySQL or MariaDB database named by TEST_DATABASE_DSN, skipping the
+// test if it is not set. Row locks are what make booking atomic, so these tests need a real database.
+func bookingTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.User{},
+		&models.Schedule{},
+		&models.SchedulingWindow{},
+		&models.SchedulingOverride{},
+		&models.HolidayCalendar{},
+		&models.Holiday{},
+		&models.SchedulingLink{},
+		&models.Client{},
+		&models.Meeting{},
+		&models.MeetingSeries{},
+		&models.MeetingNote{},
+		&models.MeetingStatusChange{},
+		&models.SlotHold{},
+		&models.WaitlistEntry{},
+		&models.IdempotencyKey{},
+		&models.GoogleAccount{},
+	); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db
+}
+
+// bookingTestLink creates an advisor available around the clock and a one-on-one link, removing
+// them when the test ends
+func bookingTestLink(t *testing.T, db *gorm.DB, maxUses *int) models.SchedulingLink {
+	suffix := time.Now().UnixNano()
+	user := models.User{
+		Email:    fmt.Sprintf("booking-test-%d@example.com", suffix),
+		Name:     "Booking Test",
+		GoogleID: fmt.Sprintf("booking-test-%d", suffix),
+		IsActive: true,
+		Timezone: "UTC",
+	}
+	if err := db.Omit("CalendarIDs").Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	for weekday := 0; weekday < 7; weekday++ {
+		window := models.SchedulingWindow{UserID: user.ID, StartHour: 0, EndHour: 24, Weekday: weekday, IsActive: true}
+		if err := db.Create(&window).Error; err != nil {
+			t.Fatalf("failed to create window: %v", err)
+		}
+	}
+
+	link := models.SchedulingLink{
+		UserID:           user.ID,
+		Title:            "Booking Test",
+		Duration:         30,
+		Seats:            1,
+		MaxUses:          maxUses,
+		HorizonMode:      "rolling",
+		MaxDaysInAdvance: 30,
+		SlotRanking:      "none",
+		CustomQuestions:  "[]",
+		IsActive:         true,
+	}
+	if err := db.Create(&link).Error; err != nil {
+		t.Fatalf("failed to create link: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Unscoped().Where("scope IN ?", []string{fmt.Sprintf("meeting:%d", link.ID), fmt.Sprintf("series:%d", link.ID)}).Delete(&models.IdempotencyKey{})
+		db.Unscoped().Where("meeting_id IN (?)", db.Model(&models.Meeting{}).Select("id").Where("user_id = ?", user.ID)).Delete(&models.MeetingStatusChange{})
+		db.Unscoped().Where("user_id = ?", user.ID).Delete(&models.Meeting{})
+		db.Unscoped().Where("user_id = ?", user.ID).Delete(&models.SlotHold{})
+		db.Unscoped().Where("user_id = ?", user.ID).Delete(&models.Client{})
+		db.Unscoped().Delete(&link)
+		db.Unscoped().Where("user_id = ?", user.ID).Delete(&models.SchedulingWindow{})
+		db.Unscoped().Where("user_id = ?", user.ID).Delete(&models.Schedule{})
+		db.Unscoped().Delete(&user)
+	})
+	return link
+}
+
+// bookInParallel sends the same number of booking requests at once, returning their status codes
+// and bodies. Each request may set its own body and Idempotency-Key.
+func bookInParallel(t *testing.T, db *gorm.DB, link models.SchedulingLink, bodies []gin.H, keys []string) ([]int, []map[string]interface{}) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/scheduling/links/:id/meetings/public", NewSchedulingHandler(db, nil, services.NewCalendarService(db)).CreatePublicMeeting)
+
+	codes := make([]int, len(bodies))
+	responses := make([]map[string]interface{}, len(bodies))
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			payload, _ := json.Marshal(bodies[i])
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/scheduling/links/%d/meetings/public", link.ID), bytes.NewReader(payload))
+			req.Header.Set("Content-Type", "application/json")
+			if keys != nil {
+				req.Header.Set(idempotencyHeader, keys[i])
+			}
+			<-start
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			codes[i] = w.Code
+			json.Unmarshal(w.Body.Bytes(), &responses[i])
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return codes, responses
+}
+
+func bookingBody(email string, start time.Time) gin.H {
+	return gin.H{
+		"client_email": email,
+		"start_time":   start.Format(time.RFC3339),
+		"end_time":     start.Add(30 * time.Minute).Format(time.RFC3339),
+		"answers":      gin.H{},
+	}
+}
+
+func countMeetings(t *testing.T, db *gorm.DB, link models.SchedulingLink) int64 {
+	var count int64
+	if err := db.Model(&models.Meeting{}).Where("scheduling_link_id = ?", link.ID).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count meetings: %v", err)
+	}
+	return count
+}
+
+func TestCreatePublicMeetingParallelBookingsOfOneSlot(t *testing.T) {
+	db := bookingTestDB(t)
+	link := bookingTestLink(t, db, nil)
+	slot := time.Now().UTC().Truncate(time.Hour).Add(48 * time.Hour)
+
+	const parallel = 10
+	bodies := make([]gin.H, parallel)
+	for i := range bodies {
+		bodies[i] = bookingBody(fmt.Sprintf("invitee-%d@example.com", i), slot)
+	}
+	codes, _ := bookInParallel(t, db, link, bodies, nil)
+
+	created := 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusBadRequest:
+		default:
+			t.Errorf("unexpected status %d", code)
+		}
+	}
+	if created != 1 {
+		t.Errorf("%d of %d parallel bookings of one slot succeeded, want 1", created, parallel)
+	}
+	if count := countMeetings(t, db, link); count != 1 {
+		t.Errorf("%d meetings were saved, want 1", count)
+	}
+}
+
+func TestCreatePublicMeetingParallelBookingsRespectMaxUses(t *testing.T) {
+	db := bookingTestDB(t)
+	maxUses := 1
+	link := bookingTestLink(t, db, &maxUses)
+	first := time.Now().UTC().Truncate(time.Hour).Add(48 * time.Hour)
+
+	// Every request asks for a different slot, so only the use limit can stop them
+	const parallel = 10
+	bodies := make([]gin.H, parallel)
+	for i := range bodies {
+		bodies[i] = bookingBody(fmt.Sprintf("invitee-%d@example.com", i), first.Add(time.Duration(i)*time.Hour))
+	}
+	codes, _ := bookInParallel(t, db, link, bodies, nil)
+
+	created := 0
+	for _, code := range codes {
+		if code == http.StatusCreated {
+			created++
+		}
+	}
+	if created != 1 {
+		t.Errorf("%d of %d parallel bookings succeeded on a link with one use, want 1", created, parallel)
+	}
+	if count := countMeetings(t, db, link); count != 1 {
+		t.Errorf("%d meetings were saved, want 1", count)
+	}
+}
+
+func TestCreatePublicMeetingIdempotencyKey(t *testing.T) {
+	db := bookingTestDB(t)
+	link := bookingTestLink(t, db, nil)
+	slot := time.Now().UTC().Truncate(time.Hour).Add(48 * time.Hour)
+
+	// A double-clicked submit sends the same request twice at once
+	body := bookingBody("invitee@example.com", slot)
+	codes, responses := bookInParallel(t, db, link, []gin.H{body, body, body}, []string{"submit-1", "submit-1", "submit-1"})
+	for i, code := range codes {
+		if code != http.StatusCreated {
+			t.Fatalf("request %d returned %d, want every retry to get the original 201", i, code)
+		}
+		if responses[i]["id"] != responses[0]["id"] {
+			t.Errorf("request %d returned meeting %v, want %v", i, responses[i]["id"], responses[0]["id"])
+		}
+	}
+	if count := countMeetings(t, db, link); count != 1 {
+		t.Errorf("%d meetings were saved, want 1", count)
+	}
+
+	// Reusing the key for a different booking is rejected
+	other := bookingBody("invitee@example.com", slot.Add(time.Hour))
+	codes, _ = bookInParallel(t, db, link, []gin.H{other}, []string{"submit-1"})
+	if codes[0] != http.StatusUnprocessableEntity {
+		t.Errorf("reused key returned %d, want %d", codes[0], http.StatusUnprocessableEntity)
+	}
+}
+
+func TestCreatePublicMeetingRejectsSlotsNotOffered(t *testing.T) {
+	db := bookingTestDB(t)
+	link := bookingTestLink(t, db, nil)
+	slot := time.Now().UTC().Truncate(time.Hour).Add(48 * time.Hour)
+
+	longer := bookingBody("invitee@example.com", slot)
+	longer["end_time"] = slot.Add(time.Hour).Format(time.RFC3339)
+	tests := []struct {
+		name   string
+		body   gin.H
+		reason string
+	}{
+		{"off the slot grid", bookingBody("invitee@example.com", slot.Add(10*time.Minute)), "off_grid"},
+		{"longer than the link's duration", longer, "invalid_duration"},
+		{"beyond max days in advance", bookingBody("invitee@example.com", slot.AddDate(0, 0, 60)), "beyond_max_days_in_advance"},
+	}
+	for _, tt := range tests {
+		codes, responses := bookInParallel(t, db, link, []gin.H{tt.body}, nil)
+		if codes[0] != http.StatusBadRequest || responses[0]["reason"] != tt.reason {
+			t.Errorf("%s: got %d with reason %v, want %d with reason %s", tt.name, codes[0], responses[0]["reason"], http.StatusBadRequest, tt.reason)
+		}
+	}
+	if count := countMeetings(t, db, link); count != 0 {
+		t.Errorf("%d meetings were saved, want 0", count)
+	}
+}
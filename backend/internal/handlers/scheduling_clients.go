@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultClientsLimit = 100
+	maxClientsLimit     = 500
+)
+
+// findOrCreateClient returns the advisor's client with the invitee's email, creating it on their
+// first booking. The details the invitee gave replace the ones kept from earlier bookings, and
+// the ones they left out are kept.
+func findOrCreateClient(tx *gorm.DB, userID uint, invitee bookingInvitee) (*models.Client, error) {
+	client := &models.Client{UserID: userID, Email: models.NormalizeEmail(invitee.ClientEmail)}
+	err := tx.Where("user_id = ? AND email = ?", client.UserID, client.Email).First(client).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		client.Name = invitee.ClientName
+		client.Phone = invitee.ClientPhone
+		client.LinkedInURL = invitee.LinkedInURL
+		client.LinkedInData = "{}"
+		return client, tx.Create(client).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if invitee.ClientName != "" && invitee.ClientName != client.Name {
+		updates["name"] = invitee.ClientName
+	}
+	if invitee.ClientPhone != "" && invitee.ClientPhone != client.Phone {
+		updates["phone"] = invitee.ClientPhone
+	}
+	if invitee.LinkedInURL != "" && invitee.LinkedInURL != client.LinkedInURL {
+		updates["linkedin_url"] = invitee.LinkedInURL
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(client).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// LinkMeetingsToClients links the meetings booked before clients were kept to their clients. It
+// is run by the server at startup.
+func (h *SchedulingHandler) LinkMeetingsToClients() error {
+	var meetings []models.Meeting
+	if err := h.db.Where("client_id IS NULL").Order("start_time").Find(&meetings).Error; err != nil {
+		return fmt.Errorf("failed to fetch meetings: %v", err)
+	}
+
+	for _, meeting := range meetings {
+		err := h.db.Transaction(func(tx *gorm.DB) error {
+			client, err := findOrCreateClient(tx, meeting.UserID, bookingInvitee{
+				ClientEmail: meeting.ClientEmail,
+				ClientName:  meeting.ClientName,
+				ClientPhone: meeting.ClientPhone,
+				LinkedInURL: meeting.LinkedInURL,
+			})
+			if err != nil {
+				return err
+			}
+			return tx.Model(&meeting).Update("client_id", client.ID).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to link meeting %d to its client: %v", meeting.ID, err)
+		}
+	}
+	return nil
+}
+
+// clientResponse formats a client in snake_case
+func clientResponse(client models.Client) gin.H {
+	return gin.H{
+		"id":                 client.ID,
+		"email":              client.Email,
+		"name":               client.Name,
+		"phone":              client.Phone,
+		"linkedin_url":       client.LinkedInURL,
+		"hubspot_contact_id": client.HubspotContactID,
+		"linkedin_data":      rawJSON(client.LinkedInData),
+		"created_at":         client.CreatedAt,
+	}
+}
+
+// rawJSON returns JSON stored as a string so it is embedded in a response as is
+func rawJSON(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return json.RawMessage(value)
+}
+
+// GetClients lists the authenticated user's clients with how many meetings each booked and when
+// the latest one starts, the client who booked most recently first. The optional q query parameter searches their
+// emails and names, and limit and offset page through the list.
+func (h *SchedulingHandler) GetClients(c *gin.Context) {
+	query := h.db.Model(&models.Client{}).Where("user_id = ?", c.GetUint("user_id"))
+	if search := c.Query("q"); search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where("email LIKE ? OR name LIKE ?", pattern, pattern)
+	}
+
+	limit := defaultClientsLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxClientsLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+			return
+		}
+		limit = parsed
+	}
+	offset := 0
+	if value := c.Query("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+		offset = parsed
+	}
+
+	query = query.Session(&gorm.Session{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clients"})
+		return
+	}
+
+	var clients []models.Client
+	// Order by when each client last booked, which enrichment and edits to the client don't change
+	lastBooked := "(SELECT MAX(meetings.created_at) FROM meetings WHERE meetings.client_id = clients.id AND meetings.deleted_at IS NULL) DESC"
+	if err := query.Order(lastBooked).Order("id DESC").Limit(limit).Offset(offset).Find(&clients).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clients"})
+		return
+	}
+
+	ids := make([]uint, len(clients))
+	for i, client := range clients {
+		ids[i] = client.ID
+	}
+	var stats []struct {
+		ClientID    uint
+		Meetings    int
+		LastMeeting time.Time
+	}
+	if err := h.db.Model(&models.Meeting{}).
+		Select("client_id, COUNT(*) AS meetings, MAX(start_time) AS last_meeting").
+		Where("client_id IN ?", ids).
+		Group("client_id").
+		Scan(&stats).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clients"})
+		return
+	}
+	byClient := make(map[uint]int, len(stats))
+	for i, stat := range stats {
+		byClient[stat.ClientID] = i
+	}
+
+	response := make([]gin.H, len(clients))
+	for i, client := range clients {
+		response[i] = clientResponse(client)
+		response[i]["meeting_count"] = 0
+		response[i]["last_meeting_at"] = nil
+		if j, ok := byClient[client.ID]; ok {
+			response[i]["meeting_count"] = stats[j].Meetings
+			response[i]["last_meeting_at"] = stats[j].LastMeeting
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"clients": response,
+		"total":   total,
+	})
+}
+
+// GetClient retrieves one of the authenticated user's clients with every meeting they booked,
+// latest first, including their answers
+func (h *SchedulingHandler) GetClient(c *gin.Context) {
+	var client models.Client
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return
+	}
+
+	var meetings []models.Meeting
+	if err := h.db.Where("client_id = ?", client.ID).Order("start_time DESC").Find(&meetings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings"})
+		return
+	}
+
+	response := clientResponse(client)
+	meetingsResponse := make([]gin.H, len(meetings))
+	for i, meeting := range meetings {
+		meetingsResponse[i] = meetingResponse(meeting)
+	}
+	response["meetings"] = meetingsResponse
+
+	c.JSON(http.StatusOK, response)
+}
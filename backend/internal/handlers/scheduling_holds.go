@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/availability"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+	"gorm.io/gorm"
+)
+
+// slotHoldDuration is how long a slot stays held for the invitee filling in the booking form
+const slotHoldDuration = 10 * time.Minute
+
+// Limits on unexpired holds, so nobody can hide a link's slots by holding all of them
+const (
+	maxHoldsPerLink      = 20 // across every invitee of a link, not counting slots offered from the waitlist
+	maxHoldsPerRequester = 3  // from one IP address across the advisor's links
+)
+
+var errTooManyHolds = errors.New("too many slot holds")
+
+// newHoldToken returns a random token identifying a slot hold
+func newHoldToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// slotHoldResponse formats a slot hold in snake_case
+func slotHoldResponse(hold models.SlotHold) gin.H {
+	return gin.H{
+		"hold_token": hold.Token,
+		"link_id":    hold.SchedulingLinkID,
+		"start_time": hold.StartTime,
+		"end_time":   hold.EndTime,
+		"expires_at": hold.ExpiresAt,
+	}
+}
+
+// CreatePublicSlotHold holds a slot of the link for a few minutes without requiring authentication,
+// hiding it from other invitees while the form is filled in. Booking with the returned hold token
+// turns the hold into the meeting, and holds that aren't booked expire on their own. Passing a
+// previous hold token as replace_hold_token releases that hold, so picking another slot doesn't
+// keep the first one held. The number of holds a link and a requester may have at once is capped.
+func (h *SchedulingHandler) CreatePublicSlotHold(c *gin.Context) {
+	link, ok := h.loadPublicLink(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		StartTime        time.Time `json:"start_time" binding:"required"`
+		ReplaceHoldToken string    `json:"replace_hold_token"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := newHoldToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hold time slot"})
+		return
+	}
+
+	now := time.Now()
+	hold := &models.SlotHold{
+		SchedulingLinkID: link.ID,
+		UserID:           link.UserID,
+		Token:            token,
+		StartTime:        input.StartTime,
+		EndTime:          input.StartTime.Add(time.Duration(link.Duration) * time.Minute),
+		ExpiresAt:        now.Add(slotHoldDuration),
+		RequesterIP:      c.ClientIP(),
+	}
+	var user models.User
+	if err := h.db.First(&user, link.UserID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user information"})
+		return
+	}
+
+	// Load the advisor's day around the slot before taking the lock, as bookings do
+	day := availability.StartOfDay(input.StartTime, user.Location())
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	in, _, err := h.loadAvailability(ctx, link, user.Location(), day.AddDate(0, 0, -1), day.AddDate(0, 0, 2))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check time slot availability"})
+		return
+	}
+
+	var explanation availability.Explanation
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		// Holds are checked and placed under the same lock as bookings
+		if _, err := lockAdvisor(tx, link.UserID); err != nil {
+			return err
+		}
+
+		// Clean up the advisor's expired holds and release the one being replaced
+		if err := tx.Unscoped().Where("user_id = ? AND expires_at <= ?", link.UserID, now).Delete(&models.SlotHold{}).Error; err != nil {
+			return err
+		}
+		if input.ReplaceHoldToken != "" {
+			if err := tx.Unscoped().Where("token = ? AND scheduling_link_id = ?", input.ReplaceHoldToken, link.ID).Delete(&models.SlotHold{}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Cap the holds of the link and of whoever is asking
+		var linkHolds, requesterHolds int64
+		// Slots offered from the waitlist are held for longer and don't take from the public's share
+		waitlistHolds := tx.Model(&models.WaitlistEntry{}).Select("hold_token").Where("hold_token IS NOT NULL")
+		if err := tx.Model(&models.SlotHold{}).Where("scheduling_link_id = ? AND token NOT IN (?)", link.ID, waitlistHolds).Count(&linkHolds).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SlotHold{}).Where("user_id = ? AND requester_ip = ?", link.UserID, hold.RequesterIP).Count(&requesterHolds).Error; err != nil {
+			return err
+		}
+		if linkHolds >= maxHoldsPerLink || requesterHolds >= maxHoldsPerRequester {
+			return errTooManyHolds
+		}
+
+		// The slot must be bookable right now to be held
+		if _, err := loadBookings(tx, link, &in, day.AddDate(0, 0, -1), day.AddDate(0, 0, 2), ""); err != nil {
+			return err
+		}
+		in.Now = time.Now()
+		if explanation = in.Explain(hold.StartTime); !explanation.Available {
+			return nil
+		}
+
+		return tx.Create(hold).Error
+	})
+	if errors.Is(err, errTooManyHolds) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many time slots are being held, please try again in a few minutes"})
+		return
+	}
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hold time slot"})
+		return
+	}
+	if !explanation.Available {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  bookingError(explanation.Reason),
+			"reason": explanation.Reason,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, slotHoldResponse(*hold))
+}
+
+// DeletePublicSlotHold releases a slot hold before it expires, such as when the invitee leaves the form
+func (h *SchedulingHandler) DeletePublicSlotHold(c *gin.Context) {
+	var hold models.SlotHold
+	if err := h.db.Where("token = ?", c.Param("token")).First(&hold).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Slot hold not found"})
+		return
+	}
+	result := h.db.Unscoped().Where("token = ?", hold.Token).Delete(&models.SlotHold{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to release time slot"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Slot hold not found"})
+		return
+	}
+	h.offerFreedTime(hold.UserID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Time slot released successfully"})
+}
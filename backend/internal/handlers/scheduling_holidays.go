@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/holidays"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+	"gorm.io/gorm"
+)
+
+// maxHolidayUploadBytes limits the size of uploaded ICS files
+const maxHolidayUploadBytes = 1 << 20
+
+// holidayResponse formats a holiday in snake_case
+func holidayResponse(holiday models.Holiday) gin.H {
+	return gin.H{
+		"id":          holiday.ID,
+		"calendar_id": holiday.HolidayCalendarID,
+		"start_date":  holiday.StartDate,
+		"end_date":    holiday.EndDate,
+		"name":        holiday.Name,
+	}
+}
+
+// holidayCalendarResponse formats a holiday calendar in snake_case
+func holidayCalendarResponse(calendar models.HolidayCalendar, count int64) gin.H {
+	return gin.H{
+		"id":            calendar.ID,
+		"schedule_id":   calendar.ScheduleID,
+		"name":          calendar.Name,
+		"source":        calendar.Source,
+		"country":       calendar.Country,
+		"holiday_count": count,
+	}
+}
+
+// holidayScope restricts a query on holidays to those applying to one of the user's schedules:
+// holidays imported for every schedule plus those imported for the schedule itself. A nil
+// scheduleID, or one that no longer exists, means the default schedule.
+func (h *SchedulingHandler) holidayScope(userID uint, scheduleID *uint) (func(*gorm.DB) *gorm.DB, error) {
+	schedule, err := h.effectiveSchedule(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendars := h.db.Model(&models.HolidayCalendar{}).Select("id").Where("user_id = ?", userID)
+	if schedule == nil {
+		calendars = calendars.Where("schedule_id IS NULL")
+	} else {
+		calendars = calendars.Where("schedule_id IS NULL OR schedule_id = ?", schedule.ID)
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("user_id = ? AND holiday_calendar_id IN (?)", userID, calendars)
+	}, nil
+}
+
+// upcomingHolidays returns the holidays that have not yet ended, optionally limited to one schedule
+func (h *SchedulingHandler) upcomingHolidays(userID uint, scheduleID *uint) ([]models.Holiday, error) {
+	var user models.User
+	if err := h.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	today := time.Now().In(user.Location()).Format("2006-01-02")
+
+	query := h.db.Where("user_id = ?", userID)
+	if scheduleID != nil {
+		scope, err := h.holidayScope(userID, scheduleID)
+		if err != nil {
+			return nil, err
+		}
+		query = h.db.Scopes(scope)
+	}
+
+	var result []models.Holiday
+	err := query.Where("end_date >= ?", today).Order("start_date").Find(&result).Error
+	return result, err
+}
+
+// importHolidayCalendar saves a calendar with its holidays, replacing a previous import of the
+// same bundled dataset for the same schedule
+func (h *SchedulingHandler) importHolidayCalendar(calendar *models.HolidayCalendar, imported []holidays.Holiday) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if calendar.Source == models.HolidaySourceBundled {
+			var previous []models.HolidayCalendar
+			query := tx.Where("user_id = ? AND source = ? AND country = ?", calendar.UserID, calendar.Source, calendar.Country)
+			if calendar.ScheduleID == nil {
+				query = query.Where("schedule_id IS NULL")
+			} else {
+				query = query.Where("schedule_id = ?", *calendar.ScheduleID)
+			}
+			if err := query.Find(&previous).Error; err != nil {
+				return err
+			}
+			for _, old := range previous {
+				if err := deleteHolidayCalendar(tx, old); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.Create(calendar).Error; err != nil {
+			return err
+		}
+		if len(imported) == 0 {
+			return nil
+		}
+		rows := make([]models.Holiday, len(imported))
+		for i, holiday := range imported {
+			rows[i] = models.Holiday{
+				HolidayCalendarID: calendar.ID,
+				UserID:            calendar.UserID,
+				StartDate:         holiday.StartDate,
+				EndDate:           holiday.EndDate,
+				Name:              holiday.Name,
+			}
+		}
+		return tx.CreateInBatches(rows, 100).Error
+	})
+}
+
+// deleteHolidayCalendar removes a calendar together with its holidays
+func deleteHolidayCalendar(tx *gorm.DB, calendar models.HolidayCalendar) error {
+	if err := tx.Where("holiday_calendar_id = ?", calendar.ID).Delete(&models.Holiday{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&calendar).Error
+}
+
+// holidayScheduleID checks that an optional schedule ID names one of the user's schedules,
+// writing the error response and returning false if it does not
+func (h *SchedulingHandler) holidayScheduleID(c *gin.Context, userID uint, scheduleID *uint) bool {
+	if scheduleID == nil {
+		return true
+	}
+	if _, err := h.resolveScheduleID(userID, scheduleID); err != nil {
+		if errors.Is(err, errScheduleNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Schedule not found"})
+			return false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import holidays"})
+		return false
+	}
+	return true
+}
+
+// GetHolidayDatasets lists the country holiday datasets bundled with the server
+func (h *SchedulingHandler) GetHolidayDatasets(c *gin.Context) {
+	response := make([]gin.H, len(holidays.Datasets))
+	for i, dataset := range holidays.Datasets {
+		response[i] = gin.H{
+			"country": dataset.Country,
+			"name":    dataset.Name,
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ImportBundledHolidays imports a bundled country dataset for the user, or for one of their schedules if schedule_id is given
+func (h *SchedulingHandler) ImportBundledHolidays(c *gin.Context) {
+	var input struct {
+		Country    string `json:"country" binding:"required"`
+		ScheduleID *uint  `json:"schedule_id"` // omit to apply to every schedule
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dataset, ok := holidays.LookupDataset(input.Country)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown holiday dataset"})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if !h.holidayScheduleID(c, userID, input.ScheduleID) {
+		return
+	}
+
+	imported, err := holidays.Bundled(dataset.Country)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load holiday dataset"})
+		return
+	}
+
+	calendar := &models.HolidayCalendar{
+		UserID:     userID,
+		ScheduleID: input.ScheduleID,
+		Name:       dataset.Name,
+		Source:     models.HolidaySourceBundled,
+		Country:    dataset.Country,
+	}
+	if err := h.importHolidayCalendar(calendar, imported); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import holidays"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, holidayCalendarResponse(*calendar, int64(len(imported))))
+}
+
+// UploadHolidayCalendar imports the all-day events of an uploaded ICS file (multipart field "file")
+// as holidays. The optional name and schedule_id form fields label the calendar and limit it to one schedule.
+func (h *SchedulingHandler) UploadHolidayCalendar(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing ICS file"})
+		return
+	}
+	if fileHeader.Size > maxHolidayUploadBytes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The ICS file is too large"})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	var scheduleID *uint
+	if value := c.PostForm("schedule_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule_id"})
+			return
+		}
+		parsed := uint(id)
+		scheduleID = &parsed
+	}
+	if !h.holidayScheduleID(c, userID, scheduleID) {
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read ICS file"})
+		return
+	}
+	defer file.Close()
+
+	imported, err := holidays.ParseICS(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ICS file: " + err.Error()})
+		return
+	}
+	if len(imported) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The ICS file has no all-day events"})
+		return
+	}
+
+	name := c.PostForm("name")
+	if name == "" {
+		name = fileHeader.Filename
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+
+	calendar := &models.HolidayCalendar{
+		UserID:     userID,
+		ScheduleID: scheduleID,
+		Name:       name,
+		Source:     models.HolidaySourceICS,
+	}
+	if err := h.importHolidayCalendar(calendar, imported); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import holidays"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, holidayCalendarResponse(*calendar, int64(len(imported))))
+}
+
+// GetHolidayCalendars retrieves the user's imported holiday calendars
+func (h *SchedulingHandler) GetHolidayCalendars(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	var calendars []models.HolidayCalendar
+	if err := h.db.Where("user_id = ?", userID).Order("name").Find(&calendars).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch holiday calendars"})
+		return
+	}
+
+	response := make([]gin.H, len(calendars))
+	for i, calendar := range calendars {
+		var count int64
+		h.db.Model(&models.Holiday{}).Where("holiday_calendar_id = ?", calendar.ID).Count(&count)
+		response[i] = holidayCalendarResponse(calendar, count)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetHolidayCalendar retrieves an imported holiday calendar with its holidays
+func (h *SchedulingHandler) GetHolidayCalendar(c *gin.Context) {
+	var calendar models.HolidayCalendar
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&calendar).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Holiday calendar not found"})
+		return
+	}
+
+	var rows []models.Holiday
+	if err := h.db.Where("holiday_calendar_id = ?", calendar.ID).Order("start_date").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch holidays"})
+		return
+	}
+
+	response := holidayCalendarResponse(calendar, int64(len(rows)))
+	holidaysResponse := make([]gin.H, len(rows))
+	for i, holiday := range rows {
+		holidaysResponse[i] = holidayResponse(holiday)
+	}
+	response["holidays"] = holidaysResponse
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteHolidayCalendar removes an imported holiday calendar and its holidays
+func (h *SchedulingHandler) DeleteHolidayCalendar(c *gin.Context) {
+	var calendar models.HolidayCalendar
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&calendar).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Holiday calendar not found"})
+		return
+	}
+
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		return deleteHolidayCalendar(tx, calendar)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete holiday calendar"})
+		return
+	}
+	h.offerFreedTime(calendar.UserID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Holiday calendar deleted successfully"})
+}
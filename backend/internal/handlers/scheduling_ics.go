@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+)
+
+const icsTimeFormat = "20060102T150405Z"
+
+// calendarEntryEvents are the status changes that change the invitee's calendar entry, each
+// raising its SEQUENCE so calendars replace the copy they have
+var calendarEntryEvents = []string{
+	models.MeetingEventAccepted,
+	models.MeetingEventDeclined,
+	models.MeetingEventExpired,
+	models.MeetingEventRescheduled,
+	models.MeetingEventCancelled,
+}
+
+// meetingUID returns the iCalendar UID of a meeting. It never changes, so every copy of the meeting
+// a calendar imports updates the same entry.
+func meetingUID(meeting models.Meeting) string {
+	domain := "advisor-scheduling"
+	if frontendURL, err := url.Parse(os.Getenv("FRONTEND_URL")); err == nil && frontendURL.Hostname() != "" {
+		domain = frontendURL.Hostname()
+	}
+	return fmt.Sprintf("meeting-%d@%s", meeting.ID, domain)
+}
+
+// meetingSequence returns the iCalendar SEQUENCE of a meeting, the number of times its calendar
+// entry changed since it was booked
+func (h *SchedulingHandler) meetingSequence(meeting models.Meeting) (int64, error) {
+	var sequence int64
+	err := h.db.Model(&models.MeetingStatusChange{}).
+		Where("meeting_id = ? AND event IN ?", meeting.ID, calendarEntryEvents).
+		Count(&sequence).Error
+	return sequence, err
+}
+
+// icsStatus maps a meeting's status to the STATUS of its calendar entry
+func icsStatus(status string) string {
+	switch status {
+	case models.MeetingStatusPending:
+		return "TENTATIVE"
+	case models.MeetingStatusCancelled, models.MeetingStatusDeclined, models.MeetingStatusExpired:
+		return "CANCELLED"
+	default:
+		return "CONFIRMED"
+	}
+}
+
+// icsText escapes a value for an iCalendar TEXT property
+func icsText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// icsParam quotes a value for an iCalendar property parameter, which can't contain quotes
+func icsParam(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "'") + `"`
+}
+
+// icsFold folds a content line into lines of at most 75 octets, without splitting a UTF-8 character
+func icsFold(line string) string {
+	var folded strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			folded.WriteString("\r\n ")
+			width = 1
+		}
+		folded.WriteRune(r)
+		width += size
+	}
+	folded.WriteString("\r\n")
+	return folded.String()
+}
+
+// meetingICS renders a meeting as an RFC 5545 calendar with one event, organized by the advisor
+// and attended by the invitee and their guests
+func meetingICS(meeting models.Meeting, link models.SchedulingLink, advisor models.User, sequence int64, now time.Time) string {
+	return meetingsICS([]models.Meeting{meeting}, link, advisor, sequence, now)
+}
+
+// meetingsICS renders meetings booked through the same link, such as the occurrences of a series,
+// as an RFC 5545 calendar with one event each
+func meetingsICS(meetings []models.Meeting, link models.SchedulingLink, advisor models.User, sequence int64, now time.Time) string {
+	summary := link.Title
+	if advisor.Name != "" {
+		summary = fmt.Sprintf("%s with %s", link.Title, advisor.Name)
+	}
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Advisor Scheduling//Booking//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+	for _, meeting := range meetings {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+meetingUID(meeting),
+			fmt.Sprintf("SEQUENCE:%d", sequence),
+			"DTSTAMP:"+now.UTC().Format(icsTimeFormat),
+			"LAST-MODIFIED:"+meeting.UpdatedAt.UTC().Format(icsTimeFormat),
+			"DTSTART:"+meeting.StartTime.UTC().Format(icsTimeFormat),
+			"DTEND:"+meeting.EndTime.UTC().Format(icsTimeFormat),
+			"SUMMARY:"+icsText(summary),
+			"STATUS:"+icsStatus(meeting.Status),
+			fmt.Sprintf("ORGANIZER;CN=%s:mailto:%s", icsParam(advisor.Name), advisor.Email),
+		)
+		for i, email := range meetingParticipants(meeting) {
+			name := ""
+			if i == 0 {
+				name = meeting.ClientName
+			}
+			if name != "" {
+				lines = append(lines, fmt.Sprintf("ATTENDEE;CN=%s;ROLE=REQ-PARTICIPANT:mailto:%s", icsParam(name), email))
+			} else {
+				lines = append(lines, fmt.Sprintf("ATTENDEE;ROLE=REQ-PARTICIPANT:mailto:%s", email))
+			}
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var ics strings.Builder
+	for _, line := range lines {
+		ics.WriteString(icsFold(line))
+	}
+	return ics.String()
+}
+
+// GetInviteeMeetingConfirmation shows the invitee the booking their manage token refers to, with
+// the advisor and everyone attending, for the confirmation page
+func (h *SchedulingHandler) GetInviteeMeetingConfirmation(c *gin.Context) {
+	meeting, ok := h.loadManagedMeeting(c)
+	if !ok {
+		return
+	}
+
+	var link models.SchedulingLink
+	if err := h.db.First(&link, meeting.SchedulingLinkID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+		return
+	}
+	var advisor models.User
+	if err := h.db.First(&advisor, meeting.UserID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user information"})
+		return
+	}
+
+	response := inviteeMeetingResponse(meeting, link)
+	response["advisor"] = gin.H{
+		"name":            advisor.Name,
+		"profile_picture": advisor.ProfilePicture,
+	}
+	response["participants"] = meetingParticipants(meeting)
+	response["ics_uid"] = meetingUID(meeting)
+	response["ics_url"] = fmt.Sprintf("/scheduling/meetings/manage/%s/ics", c.Param("token"))
+	c.JSON(http.StatusOK, response)
+}
+
+// GetInviteeMeetingICS downloads the meeting the invitee's manage token refers to as an .ics file.
+// Downloading it again after the meeting is moved updates the same calendar entry.
+func (h *SchedulingHandler) GetInviteeMeetingICS(c *gin.Context) {
+	meeting, ok := h.loadManagedMeeting(c)
+	if !ok {
+		return
+	}
+
+	var link models.SchedulingLink
+	if err := h.db.First(&link, meeting.SchedulingLinkID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+		return
+	}
+	var advisor models.User
+	if err := h.db.First(&advisor, meeting.UserID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user information"})
+		return
+	}
+	sequence, err := h.meetingSequence(meeting)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meeting history"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="meeting-%d.ics"`, meeting.ID))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(meetingICS(meeting, link, advisor, sequence, time.Now())))
+}
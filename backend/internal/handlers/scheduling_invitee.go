@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+)
+
+// manageTokenPurpose marks tokens that let an invitee manage their meeting, so they can't be
+// mistaken for login tokens
+const manageTokenPurpose = "manage_meeting"
+
+// manageTokenTTL bounds how long any manage token is valid, however far off the meeting is
+const manageTokenTTL = 365 * 24 * time.Hour
+
+var errInvalidManageToken = errors.New("invalid manage token")
+
+// manageToken signs a token that lets the invitee view, cancel or reschedule a meeting. The token
+// is tied to the meeting's nonce rather than its time, so it keeps working after the meeting is moved
+// until the meeting ends. Cancelling the meeting changes the nonce, revoking the token.
+func manageToken(meeting models.Meeting) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"purpose":    manageTokenPurpose,
+		"meeting_id": meeting.ID,
+		"nonce":      meeting.ManageNonce,
+		"iat":        now.Unix(),
+		"exp":        now.Add(manageTokenTTL).Unix(),
+	})
+	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+}
+
+// parseManageToken verifies a manage token, returning the meeting ID and nonce it was issued for
+func parseManageToken(tokenString string) (uint, string, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errInvalidManageToken
+		}
+		return []byte(os.Getenv("JWT_SECRET")), nil
+	})
+	if err != nil || !token.Valid {
+		return 0, "", errInvalidManageToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != manageTokenPurpose {
+		return 0, "", errInvalidManageToken
+	}
+	meetingID, ok := claims["meeting_id"].(float64)
+	if !ok {
+		return 0, "", errInvalidManageToken
+	}
+	nonce, ok := claims["nonce"].(string)
+	if !ok || nonce == "" {
+		return 0, "", errInvalidManageToken
+	}
+	return uint(meetingID), nonce, nil
+}
+
+// loadManagedMeeting fetches the meeting named by the token in the URL. It writes the error response
+// and returns false if the token is invalid, expired or revoked, or the meeting has ended.
+func (h *SchedulingHandler) loadManagedMeeting(c *gin.Context) (models.Meeting, bool) {
+	var meeting models.Meeting
+	meetingID, nonce, err := parseManageToken(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "This link is invalid or has expired"})
+		return meeting, false
+	}
+
+	if err := h.db.First(&meeting, meetingID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return meeting, false
+	}
+
+	// Tokens issued before the nonce changed no longer apply, and none apply once the meeting, as
+	// currently scheduled, is over
+	if subtle.ConstantTimeCompare([]byte(meeting.ManageNonce), []byte(nonce)) != 1 || !time.Now().Before(meeting.EndTime) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "This link is invalid or has expired"})
+		return meeting, false
+	}
+
+	return meeting, true
+}
+
+// inviteeMeetingResponse formats a meeting for its invitee, leaving out the advisor's notes
+func inviteeMeetingResponse(meeting models.Meeting, link models.SchedulingLink) gin.H {
+	return gin.H{
+		"id":                meeting.ID,
+		"link_id":           meeting.SchedulingLinkID,
+		"title":             link.Title,
+		"duration":          link.Duration,
+		"client_email":      meeting.ClientEmail,
+		"client_name":       meeting.ClientName,
+		"client_phone":      meeting.ClientPhone,
+		"guest_emails":      meeting.GuestEmails,
+		"start_time":        meeting.StartTime,
+		"end_time":          meeting.EndTime,
+		"status":            meeting.Status,
+		"series_id":         meeting.SeriesID,
+		"cancelled_at":      meeting.CancelledAt,
+		"cancel_reason":     meeting.CancelReason,
+		"rescheduled_at":    meeting.RescheduledAt,
+		"approval_deadline": meeting.ApprovalDeadline,
+		"decline_reason":    meeting.DeclineReason,
+	}
+}
+
+// notifyInviteeChange emails the advisor that an invitee cancelled or rescheduled a meeting
+func (h *SchedulingHandler) notifyInviteeChange(meeting models.Meeting, change string, details map[string]interface{}) {
+	if h.emailService == nil {
+		return
+	}
+	var user models.User
+	if err := h.db.First(&user, meeting.UserID).Error; err != nil {
+		fmt.Printf("Failed to fetch user for email notification: %v\n", err)
+		return
+	}
+
+	details["meeting_id"] = meeting.ID
+	participantDetails(details, meeting)
+	go func() {
+		emailCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		if err := h.emailService.SendMeetingChangeNotification(emailCtx, user.Email, change, details); err != nil {
+			// Log the error, the change itself has been saved
+			fmt.Printf("Failed to send email notification: %v\n", err)
+		}
+	}()
+}
+
+// GetInviteeMeeting shows the invitee the meeting their manage token refers to
+func (h *SchedulingHandler) GetInviteeMeeting(c *gin.Context) {
+	meeting, ok := h.loadManagedMeeting(c)
+	if !ok {
+		return
+	}
+
+	var link models.SchedulingLink
+	if err := h.db.First(&link, meeting.SchedulingLinkID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, inviteeMeetingResponse(meeting, link))
+}
+
+// CancelInviteeMeeting lets the invitee cancel their meeting with an optional reason until it starts.
+// The cancelled meeting no longer counts toward the link's maximum uses.
+func (h *SchedulingHandler) CancelInviteeMeeting(c *gin.Context) {
+	meeting, ok := h.loadManagedMeeting(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Reason string `json:"reason" binding:"max=1000"`
+	}
+	// The body is optional, as the reason is
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if meeting.Status == models.MeetingStatusCancelled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This meeting has already been cancelled"})
+		return
+	}
+	if !models.CanTransition(meeting.Status, models.MeetingStatusCancelled) || !time.Now().Before(meeting.StartTime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This meeting can no longer be cancelled"})
+		return
+	}
+
+	// A new nonce revokes the invitee's manage links along with the meeting
+	nonce, err := models.NewManageNonce()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel meeting"})
+		return
+	}
+	now := time.Now()
+	if err := changeMeetingStatus(h.db, &meeting, meetingChange{
+		to:      models.MeetingStatusCancelled,
+		event:   models.MeetingEventCancelled,
+		actor:   models.MeetingActorInvitee,
+		reason:  input.Reason,
+		updates: map[string]interface{}{"cancelled_at": now, "cancel_reason": input.Reason, "manage_nonce": nonce},
+	}); err != nil {
+		respondStatusChangeError(c, err, "Failed to cancel meeting")
+		return
+	}
+	meeting.CancelledAt = &now
+	meeting.CancelReason = input.Reason
+
+	h.offerFreedTime(meeting.UserID)
+	h.notifyInviteeChange(meeting, "cancelled", map[string]interface{}{
+		"start_time": meeting.StartTime.Format(time.RFC3339),
+		"end_time":   meeting.EndTime.Format(time.RFC3339),
+		"reason":     input.Reason,
+	})
+
+	var link models.SchedulingLink
+	h.db.First(&link, meeting.SchedulingLinkID)
+	c.JSON(http.StatusOK, inviteeMeetingResponse(meeting, link))
+}
+
+// RescheduleInviteeMeeting lets the invitee move their meeting to another slot the link offers until
+// it starts. The meeting keeps its use of the link, and its manage token keeps working.
+func (h *SchedulingHandler) RescheduleInviteeMeeting(c *gin.Context) {
+	meeting, ok := h.loadManagedMeeting(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		StartTime time.Time `json:"start_time" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if meeting.Status == models.MeetingStatusCancelled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This meeting has been cancelled"})
+		return
+	}
+	if !models.CanTransition(meeting.Status, meeting.Status) || !time.Now().Before(meeting.StartTime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This meeting can no longer be rescheduled"})
+		return
+	}
+
+	var link models.SchedulingLink
+	if err := h.db.First(&link, meeting.SchedulingLinkID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	previousStart, previousEnd := meeting.StartTime, meeting.EndTime
+	message, err := h.rescheduleMeeting(ctx, &meeting, link, input.StartTime, models.MeetingActorInvitee, nil)
+	if err != nil {
+		respondStatusChangeError(c, err, "Failed to reschedule meeting")
+		return
+	}
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	h.offerFreedTime(meeting.UserID)
+	h.notifyInviteeChange(meeting, "rescheduled", map[string]interface{}{
+		"previous_start_time": previousStart.Format(time.RFC3339),
+		"previous_end_time":   previousEnd.Format(time.RFC3339),
+		"start_time":          meeting.StartTime.Format(time.RFC3339),
+		"end_time":            meeting.EndTime.Format(time.RFC3339),
+	})
+
+	c.JSON(http.StatusOK, inviteeMeetingResponse(meeting, link))
+}
@@ -0,0 +1,398 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/availability"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+	"gorm.io/gorm"
+)
+
+// Page sizes for listing meetings
+const (
+	defaultMeetingsLimit = 100
+	maxMeetingsLimit     = 500
+)
+
+// meetingNoteResponse formats an internal meeting note in snake_case
+func meetingNoteResponse(note models.MeetingNote) gin.H {
+	return gin.H{
+		"id":         note.ID,
+		"meeting_id": note.MeetingID,
+		"body":       note.Body,
+		"created_at": note.CreatedAt,
+	}
+}
+
+// rescheduleMeeting moves a meeting to newStart if it could be booked there, except that the meeting
+// itself neither conflicts with the new slot nor takes an extra use of the link. Like bookings, the
+// check and the update run in one transaction holding the advisor's lock, so a concurrent booking or
+// reschedule can't take the same slot. It returns the message explaining why the move isn't allowed,
+// or an empty string once the meeting has moved.
+func (h *SchedulingHandler) rescheduleMeeting(ctx context.Context, meeting *models.Meeting, link models.SchedulingLink, newStart time.Time, actor string, actorID *uint) (string, error) {
+	var user models.User
+	if err := h.db.First(&user, link.UserID).Error; err != nil {
+		return "", err
+	}
+	userLoc := user.Location()
+
+	// Calendar busy time is fetched before taking the lock, as bookings do
+	day := availability.StartOfDay(newStart, userLoc)
+	in, _, err := h.loadAvailability(ctx, link, userLoc, day.AddDate(0, 0, -1), day.AddDate(0, 0, 2))
+	if err != nil {
+		return "", err
+	}
+
+	message := ""
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockAdvisor(tx, link.UserID); err != nil {
+			return err
+		}
+
+		// Check against the advisor's other meetings, keeping the holds
+		meetings, err := loadBookings(tx, link, &in, day.AddDate(0, 0, -1), day.AddDate(0, 0, 2), "")
+		if err != nil {
+			return err
+		}
+		held := []availability.Meeting{}
+		for _, loaded := range in.Meetings {
+			if loaded.Held {
+				held = append(held, loaded)
+			}
+		}
+		others := []models.Meeting{}
+		for _, other := range meetings {
+			if other.ID != meeting.ID {
+				others = append(others, other)
+			}
+		}
+		in.Meetings = append(availabilityMeetings(others), held...)
+		if link.MaxUses != nil {
+			in.Uses--
+		}
+		in.Now = time.Now()
+
+		if explanation := in.Explain(newStart); !explanation.Available {
+			message = bookingError(explanation.Reason)
+			return nil
+		}
+
+		// The same invitee can only take one seat of a group session
+		for _, other := range others {
+			if link.Seats > 1 && other.SchedulingLinkID == link.ID && other.StartTime.Equal(newStart) &&
+				strings.EqualFold(other.ClientEmail, meeting.ClientEmail) {
+				message = "The invitee has already booked this session"
+				return nil
+			}
+		}
+
+		return moveMeeting(tx, meeting, newStart, actor, actorID)
+	})
+	return message, err
+}
+
+// moveMeeting saves a meeting at a new start time, keeping its length and status, and records the
+// reschedule in its history
+func moveMeeting(db *gorm.DB, meeting *models.Meeting, newStart time.Time, actor string, actorID *uint) error {
+	now := time.Now()
+	newEnd := newStart.Add(meeting.EndTime.Sub(meeting.StartTime))
+	if err := changeMeetingStatus(db, meeting, meetingChange{
+		to:      meeting.Status,
+		event:   models.MeetingEventRescheduled,
+		actor:   actor,
+		actorID: actorID,
+		updates: map[string]interface{}{
+			"start_time":     newStart,
+			"end_time":       newEnd,
+			"rescheduled_at": now,
+		},
+	}); err != nil {
+		return err
+	}
+	meeting.StartTime = newStart
+	meeting.EndTime = newEnd
+	meeting.RescheduledAt = &now
+	return nil
+}
+
+// loadOwnMeeting fetches the meeting named in the URL if it belongs to the authenticated user,
+// writing the error response and returning false if it doesn't
+func (h *SchedulingHandler) loadOwnMeeting(c *gin.Context) (models.Meeting, bool) {
+	var meeting models.Meeting
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&meeting).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+		return meeting, false
+	}
+	return meeting, true
+}
+
+// GetMeetings lists the authenticated user's meetings across all of their links. The optional
+// from and to dates (yyyy-mm-dd, inclusive, in the user's time zone), link_id, status (comma
+// separated), client_email and client_id query parameters filter the list, and limit and offset page through it.
+func (h *SchedulingHandler) GetMeetings(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	var user models.User
+	if err := h.db.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user information"})
+		return
+	}
+	userLoc := user.Location()
+
+	query := h.db.Model(&models.Meeting{}).Where("user_id = ?", userID)
+	if from := c.Query("from"); from != "" {
+		day, err := time.ParseInLocation("2006-01-02", from, userLoc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date format (expected: yyyy-mm-dd)"})
+			return
+		}
+		query = query.Where("start_time >= ?", day)
+	}
+	if to := c.Query("to"); to != "" {
+		day, err := time.ParseInLocation("2006-01-02", to, userLoc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date format (expected: yyyy-mm-dd)"})
+			return
+		}
+		query = query.Where("start_time < ?", day.AddDate(0, 0, 1))
+	}
+	if linkID := c.Query("link_id"); linkID != "" {
+		id, err := strconv.ParseUint(linkID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid link_id"})
+			return
+		}
+		query = query.Where("scheduling_link_id = ?", id)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status IN ?", strings.Split(status, ","))
+	}
+	if email := c.Query("client_email"); email != "" {
+		query = query.Where("LOWER(TRIM(client_email)) = ?", models.NormalizeEmail(email))
+	}
+	if clientID := c.Query("client_id"); clientID != "" {
+		id, err := strconv.ParseUint(clientID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client_id"})
+			return
+		}
+		query = query.Where("client_id = ?", id)
+	}
+
+	limit := defaultMeetingsLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxMeetingsLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+			return
+		}
+		limit = parsed
+	}
+	offset := 0
+	if value := c.Query("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+		offset = parsed
+	}
+
+	// Count and fetch from separate sessions so the count doesn't leak into the page query
+	query = query.Session(&gorm.Session{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings"})
+		return
+	}
+
+	var meetings []models.Meeting
+	if err := query.Order("start_time").Limit(limit).Offset(offset).Find(&meetings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings"})
+		return
+	}
+
+	response := make([]gin.H, len(meetings))
+	for i, meeting := range meetings {
+		response[i] = meetingResponse(meeting)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"meetings": response,
+		"total":    total,
+	})
+}
+
+// GetMeeting retrieves one of the authenticated user's meetings with its link, internal notes and status history
+func (h *SchedulingHandler) GetMeeting(c *gin.Context) {
+	meeting, ok := h.loadOwnMeeting(c)
+	if !ok {
+		return
+	}
+
+	var link models.SchedulingLink
+	if err := h.db.First(&link, meeting.SchedulingLinkID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduling link"})
+		return
+	}
+
+	var notes []models.MeetingNote
+	if err := h.db.Where("meeting_id = ?", meeting.ID).Order("created_at").Find(&notes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meeting notes"})
+		return
+	}
+
+	response := meetingResponse(meeting)
+	response["link"] = gin.H{
+		"id":       link.ID,
+		"title":    link.Title,
+		"duration": link.Duration,
+	}
+	notesResponse := make([]gin.H, len(notes))
+	for i, note := range notes {
+		notesResponse[i] = meetingNoteResponse(note)
+	}
+	response["notes"] = notesResponse
+
+	var history []models.MeetingStatusChange
+	if err := h.db.Where("meeting_id = ?", meeting.ID).Order("id").Find(&history).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meeting history"})
+		return
+	}
+	historyResponse := make([]gin.H, len(history))
+	for i, change := range history {
+		historyResponse[i] = meetingStatusChangeResponse(change)
+	}
+	response["history"] = historyResponse
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RescheduleMeeting moves one of the authenticated user's meetings to another slot its link offers
+func (h *SchedulingHandler) RescheduleMeeting(c *gin.Context) {
+	meeting, ok := h.loadOwnMeeting(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		StartTime time.Time `json:"start_time" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !models.CanTransition(meeting.Status, meeting.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This meeting can no longer be rescheduled"})
+		return
+	}
+
+	var link models.SchedulingLink
+	if err := h.db.First(&link, meeting.SchedulingLinkID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduling link"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	message, err := h.rescheduleMeeting(ctx, &meeting, link, input.StartTime, models.MeetingActorAdvisor, advisorActor(c))
+	if err != nil {
+		respondStatusChangeError(c, err, "Failed to reschedule meeting")
+		return
+	}
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	h.offerFreedTime(meeting.UserID)
+
+	c.JSON(http.StatusOK, meetingResponse(meeting))
+}
+
+// MarkMeetingNoShow records that the invitee did not attend a meeting that has already started.
+// A meeting marked as completed by mistake can be corrected.
+func (h *SchedulingHandler) MarkMeetingNoShow(c *gin.Context) {
+	h.markMeetingAttendance(c, models.MeetingStatusNoShow, models.MeetingEventNoShow, "Only scheduled or completed meetings can be marked as a no-show")
+}
+
+// CompleteMeeting records that a meeting that has already started took place. A meeting marked as
+// a no-show by mistake can be corrected.
+func (h *SchedulingHandler) CompleteMeeting(c *gin.Context) {
+	h.markMeetingAttendance(c, models.MeetingStatusCompleted, models.MeetingEventCompleted, "Only scheduled or no-show meetings can be marked as completed")
+}
+
+// markMeetingAttendance moves one of the authenticated user's meetings that has started to the
+// completed or no-show status, with an optional reason
+func (h *SchedulingHandler) markMeetingAttendance(c *gin.Context, status string, event string, invalid string) {
+	meeting, ok := h.loadOwnMeeting(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Reason string `json:"reason" binding:"max=1000"`
+	}
+	// The body is optional, as the reason is
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !models.CanTransition(meeting.Status, status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalid})
+		return
+	}
+	if meeting.StartTime.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This meeting has not started yet"})
+		return
+	}
+
+	if err := changeMeetingStatus(h.db, &meeting, meetingChange{
+		to:      status,
+		event:   event,
+		actor:   models.MeetingActorAdvisor,
+		actorID: advisorActor(c),
+		reason:  input.Reason,
+	}); err != nil {
+		respondStatusChangeError(c, err, "Failed to update meeting")
+		return
+	}
+
+	c.JSON(http.StatusOK, meetingResponse(meeting))
+}
+
+// AddMeetingNote adds an internal note to one of the authenticated user's meetings. Notes are
+// never shown to the invitee.
+func (h *SchedulingHandler) AddMeetingNote(c *gin.Context) {
+	meeting, ok := h.loadOwnMeeting(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Body string `json:"body" binding:"required,max=10000"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	note := &models.MeetingNote{
+		MeetingID: meeting.ID,
+		UserID:    meeting.UserID,
+		Body:      input.Body,
+	}
+	if err := h.db.Create(note).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add meeting note"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, meetingNoteResponse(*note))
+}
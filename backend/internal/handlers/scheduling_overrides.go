@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+)
+
+// maxOverrideDays limits how many dates a single override may cover
+const maxOverrideDays = 366
+
+// overrideInput is the request body shared by override create and update
+type overrideInput struct {
+	StartDate   string  `json:"start_date" binding:"required"` // yyyy-mm-dd
+	EndDate     string  `json:"end_date"`                      // yyyy-mm-dd, defaults to start_date
+	StartTime   *string `json:"start_time"`                    // HH:MM, omit together with end_time for the whole day
+	EndTime     *string `json:"end_time"`                      // HH:MM, 24:00 allowed
+	IsAvailable *bool   `json:"is_available" binding:"required"`
+	Reason      string  `json:"reason"`
+	ScheduleID  *uint   `json:"schedule_id"` // omit to use the default schedule, or to keep the current one on update
+}
+
+// apply validates the input and copies it onto an override
+func (input overrideInput) apply(override *models.SchedulingOverride) error {
+	startDate, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid start_date format (expected: yyyy-mm-dd)")
+	}
+	endDate := startDate
+	if input.EndDate != "" {
+		endDate, err = time.Parse("2006-01-02", input.EndDate)
+		if err != nil {
+			return fmt.Errorf("invalid end_date format (expected: yyyy-mm-dd)")
+		}
+	}
+	if endDate.Before(startDate) {
+		return fmt.Errorf("end_date must not be before start_date")
+	}
+	if endDate.Sub(startDate) >= maxOverrideDays*24*time.Hour {
+		return fmt.Errorf("an override may cover at most %d days", maxOverrideDays)
+	}
+
+	var startMinute, endMinute *int
+	if input.StartTime != nil || input.EndTime != nil {
+		if input.StartTime == nil || input.EndTime == nil {
+			return fmt.Errorf("start_time and end_time must be provided together")
+		}
+		start, err := parseClock(*input.StartTime)
+		if err != nil {
+			return fmt.Errorf("invalid start_time: %v", err)
+		}
+		end, err := parseClock(*input.EndTime)
+		if err != nil {
+			return fmt.Errorf("invalid end_time: %v", err)
+		}
+		if start >= end {
+			return fmt.Errorf("start_time must be before end_time")
+		}
+		startMinute, endMinute = &start, &end
+	}
+
+	override.StartDate = startDate.Format("2006-01-02")
+	override.EndDate = endDate.Format("2006-01-02")
+	override.StartMinute = startMinute
+	override.EndMinute = endMinute
+	override.IsAvailable = *input.IsAvailable
+	override.Reason = input.Reason
+	return nil
+}
+
+// parseClock converts an HH:MM time of day into minutes after midnight
+func parseClock(value string) (int, error) {
+	// 24:00 marks the end of the day
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM")
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// formatClock converts minutes after midnight into HH:MM
+func formatClock(minutes *int) interface{} {
+	if minutes == nil {
+		return nil
+	}
+	return fmt.Sprintf("%02d:%02d", *minutes/60, *minutes%60)
+}
+
+// overrideResponse formats an override in snake_case
+func overrideResponse(override models.SchedulingOverride) gin.H {
+	return gin.H{
+		"id":           override.ID,
+		"schedule_id":  override.ScheduleID,
+		"start_date":   override.StartDate,
+		"end_date":     override.EndDate,
+		"start_time":   formatClock(override.StartMinute),
+		"end_time":     formatClock(override.EndMinute),
+		"all_day":      override.AllDay(),
+		"is_available": override.IsAvailable,
+		"reason":       override.Reason,
+	}
+}
+
+// CreateSchedulingOverride creates a date-specific availability override
+func (h *SchedulingHandler) CreateSchedulingOverride(c *gin.Context) {
+	var input overrideInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	override := &models.SchedulingOverride{UserID: userID}
+	if err := input.apply(override); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scheduleID, err := h.resolveScheduleID(userID, input.ScheduleID)
+	if err != nil {
+		if errors.Is(err, errScheduleNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Schedule not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create scheduling override"})
+		return
+	}
+	override.ScheduleID = &scheduleID
+
+	if err := h.db.Create(override).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create scheduling override"})
+		return
+	}
+	h.offerFreedTime(userID)
+
+	c.JSON(http.StatusCreated, overrideResponse(*override))
+}
+
+// GetSchedulingOverrides retrieves the authenticated user's overrides, optionally limited to a from/to date range
+func (h *SchedulingHandler) GetSchedulingOverrides(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	query := h.db.Where("user_id = ?", userID)
+
+	// Optionally limit the overrides to one schedule
+	scheduleID, err := queryScheduleID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule_id"})
+		return
+	}
+	if scheduleID != nil {
+		scope, err := h.scheduleScope(userID, scheduleID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduling overrides"})
+			return
+		}
+		query = h.db.Scopes(scope)
+	}
+
+	if from := c.Query("from"); from != "" {
+		if _, err := time.Parse("2006-01-02", from); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date format (expected: yyyy-mm-dd)"})
+			return
+		}
+		query = query.Where("end_date >= ?", from)
+	}
+	if to := c.Query("to"); to != "" {
+		if _, err := time.Parse("2006-01-02", to); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date format (expected: yyyy-mm-dd)"})
+			return
+		}
+		query = query.Where("start_date <= ?", to)
+	}
+
+	var overrides []models.SchedulingOverride
+	if err := query.Order("start_date").Find(&overrides).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduling overrides"})
+		return
+	}
+
+	response := make([]gin.H, len(overrides))
+	for i, override := range overrides {
+		response[i] = overrideResponse(override)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSchedulingOverride retrieves a single override
+func (h *SchedulingHandler) GetSchedulingOverride(c *gin.Context) {
+	var override models.SchedulingOverride
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&override).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling override not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, overrideResponse(override))
+}
+
+// UpdateSchedulingOverride replaces the dates, times and type of an override
+func (h *SchedulingHandler) UpdateSchedulingOverride(c *gin.Context) {
+	var override models.SchedulingOverride
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&override).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling override not found"})
+		return
+	}
+
+	var input overrideInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := input.apply(&override); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.ScheduleID != nil {
+		scheduleID, err := h.resolveScheduleID(override.UserID, input.ScheduleID)
+		if err != nil {
+			if errors.Is(err, errScheduleNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Schedule not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update scheduling override"})
+			return
+		}
+		override.ScheduleID = &scheduleID
+	}
+
+	if err := h.db.Save(&override).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update scheduling override"})
+		return
+	}
+	h.offerFreedTime(override.UserID)
+
+	c.JSON(http.StatusOK, overrideResponse(override))
+}
+
+// DeleteSchedulingOverride deletes an override
+func (h *SchedulingHandler) DeleteSchedulingOverride(c *gin.Context) {
+	var override models.SchedulingOverride
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&override).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling override not found"})
+		return
+	}
+
+	if err := h.db.Delete(&override).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete scheduling override"})
+		return
+	}
+	h.offerFreedTime(override.UserID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Scheduling override deleted successfully"})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/advisor-scheduling/internal/models"
+)
+
+// bookingInvitee is the part of a public booking that says who attends: the invitee booking it and
+// the guests they bring along
+type bookingInvitee struct {
+	ClientEmail string   `json:"client_email" binding:"required,email"`
+	ClientName  string   `json:"client_name" binding:"max=255"`
+	ClientPhone string   `json:"client_phone" binding:"max=32"`
+	GuestEmails []string `json:"guest_emails" binding:"omitempty,max=10,dive,email"`
+	LinkedInURL string   `json:"linkedin_url"`
+}
+
+// guestList returns the invitee's guests without duplicates or the invitee themselves, or an error
+// to show the invitee if the link doesn't allow that many
+func (invitee bookingInvitee) guestList(link models.SchedulingLink) (models.StringSlice, error) {
+	guests := models.StringSlice{}
+	seen := map[string]bool{strings.ToLower(invitee.ClientEmail): true}
+	for _, email := range invitee.GuestEmails {
+		email = strings.TrimSpace(email)
+		if seen[strings.ToLower(email)] {
+			continue
+		}
+		seen[strings.ToLower(email)] = true
+		guests = append(guests, email)
+	}
+
+	if len(guests) > link.MaxGuests {
+		if link.MaxGuests == 0 {
+			return nil, fmt.Errorf("This scheduling link doesn't allow guests")
+		}
+		return nil, fmt.Errorf("This scheduling link allows at most %d guests", link.MaxGuests)
+	}
+	return guests, nil
+}
+
+// meetingParticipants returns the email addresses of everyone attending a meeting besides the
+// advisor, the invitee first
+func meetingParticipants(meeting models.Meeting) []string {
+	return append([]string{meeting.ClientEmail}, meeting.GuestEmails...)
+}
+
+// participantDetails adds who attends a meeting to the details of an email notification
+func participantDetails(details map[string]interface{}, meeting models.Meeting) map[string]interface{} {
+	details["client_email"] = meeting.ClientEmail
+	details["client_name"] = meeting.ClientName
+	details["client_phone"] = meeting.ClientPhone
+	details["guest_emails"] = strings.Join(meeting.GuestEmails, ", ")
+	if meeting.ClientID != nil {
+		details["client_id"] = *meeting.ClientID
+	}
+	return details
+}
@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+	"gorm.io/gorm"
+)
+
+// defaultScheduleName names the default schedule created for users who have not set one up
+const defaultScheduleName = "Working hours"
+
+// errScheduleNotFound is returned when a schedule ID does not name one of the user's schedules
+var errScheduleNotFound = errors.New("schedule not found")
+
+// scheduleResponse formats a schedule in snake_case
+func scheduleResponse(schedule models.Schedule) gin.H {
+	return gin.H{
+		"id":         schedule.ID,
+		"name":       schedule.Name,
+		"is_default": schedule.IsDefault,
+	}
+}
+
+// ensureDefaultSchedule returns the user's default schedule, creating it on first use. Windows and
+// overrides created before schedules existed have no schedule and are moved into the new default.
+func ensureDefaultSchedule(db *gorm.DB, userID uint) (models.Schedule, error) {
+	var schedule models.Schedule
+	err := db.Where("user_id = ? AND is_default = ?", userID, true).First(&schedule).Error
+	if err == nil {
+		return schedule, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return schedule, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		schedule = models.Schedule{UserID: userID, Name: defaultScheduleName, IsDefault: true}
+		if err := tx.Create(&schedule).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SchedulingWindow{}).Where("user_id = ? AND schedule_id IS NULL", userID).Update("schedule_id", schedule.ID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.SchedulingOverride{}).Where("user_id = ? AND schedule_id IS NULL", userID).Update("schedule_id", schedule.ID).Error
+	})
+	return schedule, err
+}
+
+// resolveScheduleID returns the schedule new windows and overrides should belong to: the requested
+// schedule if it is one of the user's, or the user's default schedule if none is requested
+func (h *SchedulingHandler) resolveScheduleID(userID uint, requested *uint) (uint, error) {
+	if requested == nil {
+		schedule, err := ensureDefaultSchedule(h.db, userID)
+		return schedule.ID, err
+	}
+
+	var schedule models.Schedule
+	if err := h.db.Where("id = ? AND user_id = ?", *requested, userID).First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errScheduleNotFound
+		}
+		return 0, err
+	}
+	return schedule.ID, nil
+}
+
+// effectiveSchedule returns the schedule a link or request uses: the requested one if it is one of
+// the user's, otherwise the default. It returns nil if the user has never set up schedules.
+func (h *SchedulingHandler) effectiveSchedule(userID uint, scheduleID *uint) (*models.Schedule, error) {
+	var schedule models.Schedule
+	if scheduleID != nil {
+		err := h.db.Where("id = ? AND user_id = ?", *scheduleID, userID).First(&schedule).Error
+		if err == nil {
+			return &schedule, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	err := h.db.Where("user_id = ? AND is_default = ?", userID, true).First(&schedule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// scheduleScope restricts a query on windows or overrides to one of the user's schedules. A nil
+// scheduleID, or one that no longer exists, means the default schedule, which also owns any rows
+// created before schedules existed.
+func (h *SchedulingHandler) scheduleScope(userID uint, scheduleID *uint) (func(*gorm.DB) *gorm.DB, error) {
+	schedule, err := h.effectiveSchedule(userID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case schedule == nil:
+		return func(db *gorm.DB) *gorm.DB {
+			return db.Where("user_id = ? AND schedule_id IS NULL", userID)
+		}, nil
+	case schedule.IsDefault:
+		return func(db *gorm.DB) *gorm.DB {
+			return db.Where("user_id = ? AND (schedule_id = ? OR schedule_id IS NULL)", userID, schedule.ID)
+		}, nil
+	default:
+		return func(db *gorm.DB) *gorm.DB {
+			return db.Where("user_id = ? AND schedule_id = ?", userID, schedule.ID)
+		}, nil
+	}
+}
+
+// queryScheduleID parses the optional schedule_id query parameter used to filter windows and overrides
+func queryScheduleID(c *gin.Context) (*uint, error) {
+	value := c.Query("schedule_id")
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	scheduleID := uint(id)
+	return &scheduleID, nil
+}
+
+// setDefaultSchedule makes a schedule the user's only default one
+func setDefaultSchedule(tx *gorm.DB, schedule *models.Schedule) error {
+	if err := tx.Model(&models.Schedule{}).Where("user_id = ? AND id <> ?", schedule.UserID, schedule.ID).Update("is_default", false).Error; err != nil {
+		return err
+	}
+	schedule.IsDefault = true
+	return tx.Model(schedule).Update("is_default", true).Error
+}
+
+// CreateSchedule creates a named schedule, optionally making it the default
+func (h *SchedulingHandler) CreateSchedule(c *gin.Context) {
+	var input struct {
+		Name      string `json:"name" binding:"required,max=100"`
+		IsDefault bool   `json:"is_default"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Make sure existing windows and overrides keep a home before another schedule appears
+	userID := c.GetUint("user_id")
+	if _, err := ensureDefaultSchedule(h.db, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create schedule"})
+		return
+	}
+
+	schedule := &models.Schedule{UserID: userID, Name: input.Name}
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(schedule).Error; err != nil {
+			return err
+		}
+		if input.IsDefault {
+			return setDefaultSchedule(tx, schedule)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create schedule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, scheduleResponse(*schedule))
+}
+
+// GetSchedules retrieves the authenticated user's schedules, default first
+func (h *SchedulingHandler) GetSchedules(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if _, err := ensureDefaultSchedule(h.db, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch schedules"})
+		return
+	}
+
+	var schedules []models.Schedule
+	if err := h.db.Where("user_id = ?", userID).Order("is_default DESC, name").Find(&schedules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch schedules"})
+		return
+	}
+
+	response := make([]gin.H, len(schedules))
+	for i, schedule := range schedules {
+		response[i] = scheduleResponse(schedule)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSchedule retrieves a schedule along with its windows, overrides and holidays
+func (h *SchedulingHandler) GetSchedule(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	var schedule models.Schedule
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&schedule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		return
+	}
+
+	scope, err := h.scheduleScope(userID, &schedule.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch schedule"})
+		return
+	}
+
+	var windows []models.SchedulingWindow
+	if err := h.db.Scopes(scope).Where("is_active = ?", true).Order("weekday, start_hour, start_minute").Find(&windows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduling windows"})
+		return
+	}
+	var overrides []models.SchedulingOverride
+	if err := h.db.Scopes(scope).Order("start_date").Find(&overrides).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduling overrides"})
+		return
+	}
+
+	holidayScope, err := h.holidayScope(userID, &schedule.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch schedule"})
+		return
+	}
+	var holidays []models.Holiday
+	if err := h.db.Scopes(holidayScope).Order("start_date").Find(&holidays).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch holidays"})
+		return
+	}
+
+	response := scheduleResponse(schedule)
+	windowsResponse := make([]gin.H, len(windows))
+	for i, window := range windows {
+		windowsResponse[i] = windowResponse(window)
+	}
+	overridesResponse := make([]gin.H, len(overrides))
+	for i, override := range overrides {
+		overridesResponse[i] = overrideResponse(override)
+	}
+	holidaysResponse := make([]gin.H, len(holidays))
+	for i, holiday := range holidays {
+		holidaysResponse[i] = holidayResponse(holiday)
+	}
+	response["windows"] = windowsResponse
+	response["overrides"] = overridesResponse
+	response["holidays"] = holidaysResponse
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateSchedule renames a schedule or makes it the default
+func (h *SchedulingHandler) UpdateSchedule(c *gin.Context) {
+	var schedule models.Schedule
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&schedule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		return
+	}
+
+	var input struct {
+		Name      *string `json:"name" binding:"omitempty,min=1,max=100"`
+		IsDefault *bool   `json:"is_default"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// There is always exactly one default, so it moves by marking another schedule as default
+	if input.IsDefault != nil && !*input.IsDefault && schedule.IsDefault {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Mark another schedule as default instead"})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if input.Name != nil {
+			schedule.Name = *input.Name
+			if err := tx.Model(&schedule).Update("name", schedule.Name).Error; err != nil {
+				return err
+			}
+		}
+		if input.IsDefault != nil && *input.IsDefault && !schedule.IsDefault {
+			return setDefaultSchedule(tx, &schedule)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update schedule"})
+		return
+	}
+	h.offerFreedTime(schedule.UserID)
+
+	c.JSON(http.StatusOK, scheduleResponse(schedule))
+}
+
+// DeleteSchedule deletes a schedule with its windows, overrides and holiday calendars. Links using it fall back to the default schedule.
+func (h *SchedulingHandler) DeleteSchedule(c *gin.Context) {
+	var schedule models.Schedule
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&schedule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		return
+	}
+
+	if schedule.IsDefault {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The default schedule cannot be deleted"})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("schedule_id = ?", schedule.ID).Delete(&models.SchedulingWindow{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("schedule_id = ?", schedule.ID).Delete(&models.SchedulingOverride{}).Error; err != nil {
+			return err
+		}
+		var calendars []models.HolidayCalendar
+		if err := tx.Where("schedule_id = ?", schedule.ID).Find(&calendars).Error; err != nil {
+			return err
+		}
+		for _, calendar := range calendars {
+			if err := deleteHolidayCalendar(tx, calendar); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&models.SchedulingLink{}).Where("schedule_id = ?", schedule.ID).Update("schedule_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&schedule).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete schedule"})
+		return
+	}
+	h.offerFreedTime(schedule.UserID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Schedule deleted successfully"})
+}
+
+// UpdateLinkSchedule sets which schedule a link offers slots from. A null schedule_id means the default schedule.
+func (h *SchedulingHandler) UpdateLinkSchedule(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	var link models.SchedulingLink
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&link).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+		return
+	}
+
+	var input struct {
+		ScheduleID *uint `json:"schedule_id"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.ScheduleID != nil {
+		if _, err := h.resolveScheduleID(userID, input.ScheduleID); err != nil {
+			if errors.Is(err, errScheduleNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Schedule not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update scheduling link"})
+			return
+		}
+	}
+
+	link.ScheduleID = input.ScheduleID
+	if err := h.db.Model(&link).Update("schedule_id", input.ScheduleID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update scheduling link"})
+		return
+	}
+	h.offerFreedTime(userID)
+
+	c.JSON(http.StatusOK, gin.H{"id": link.ID, "schedule_id": link.ScheduleID})
+}
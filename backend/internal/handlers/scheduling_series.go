@@ -0,0 +1,420 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/availability"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+	"gorm.io/gorm"
+)
+
+// maxSeriesInterval limits how far apart occurrences of a series may be, in weeks or months
+const maxSeriesInterval = 4
+
+// seriesOccurrences lays out the start times of a series in the advisor's time zone, so weekly
+// occurrences keep their wall clock time across DST changes
+func seriesOccurrences(first time.Time, frequency string, interval, count int, userLoc *time.Location) []time.Time {
+	local := first.In(userLoc)
+	starts := make([]time.Time, count)
+	for i := range starts {
+		if frequency == models.RecurrenceMonthly {
+			starts[i] = local.AddDate(0, interval*i, 0)
+		} else {
+			starts[i] = local.AddDate(0, 0, 7*interval*i)
+		}
+	}
+	return starts
+}
+
+// seriesResponse formats a series and its occurrences in snake_case
+func seriesResponse(series models.MeetingSeries, meetings []models.Meeting) gin.H {
+	occurrences := make([]gin.H, len(meetings))
+	for i, meeting := range meetings {
+		occurrences[i] = meetingResponse(meeting)
+	}
+	return gin.H{
+		"id":           series.ID,
+		"link_id":      series.SchedulingLinkID,
+		"client_email": series.ClientEmail,
+		"frequency":    series.Frequency,
+		"interval":     series.Interval,
+		"occurrences":  series.Occurrences,
+		"meetings":     occurrences,
+	}
+}
+
+// CreatePublicMeetingSeries books a recurring series of meetings in one submission without requiring
+// authentication. Every occurrence must be bookable, otherwise nothing is booked and the response
+// lists which occurrences are unavailable and why. Like single bookings, series are booked atomically
+// and honor the Idempotency-Key header.
+func (h *SchedulingHandler) CreatePublicMeetingSeries(c *gin.Context) {
+	idempotency, ok := readIdempotency(c, "series:"+c.Param("id"))
+	if !ok {
+		return
+	}
+	if result, err := idempotency.replay(h.db); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create meeting series"})
+		return
+	} else if result != nil {
+		c.JSON(result.status, result.body)
+		return
+	}
+
+	link, ok := h.loadPublicLink(c)
+	if !ok {
+		return
+	}
+
+	if link.MaxOccurrences < 2 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This scheduling link does not allow recurring bookings"})
+		return
+	}
+
+	var input struct {
+		bookingInvitee
+		StartTime  time.Time         `json:"start_time" binding:"required"` // first occurrence
+		Answers    map[string]string `json:"answers" binding:"required"`
+		Recurrence struct {
+			Frequency string `json:"frequency" binding:"required,oneof=weekly monthly"`
+			Interval  int    `json:"interval" binding:"omitempty,min=1"` // defaults to 1
+			Count     int    `json:"count" binding:"required,min=2"`
+		} `json:"recurrence" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	guests, err := input.guestList(link)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recurrence := input.Recurrence
+	if recurrence.Interval == 0 {
+		recurrence.Interval = 1
+	}
+	if recurrence.Interval > maxSeriesInterval {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Occurrences may be at most %d weeks or months apart", maxSeriesInterval)})
+		return
+	}
+	if recurrence.Count > link.MaxOccurrences {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("This scheduling link allows at most %d occurrences", link.MaxOccurrences)})
+		return
+	}
+
+	// Load the advisor, in whose time zone the occurrences are laid out
+	var user models.User
+	if err := h.db.First(&user, link.UserID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user information"})
+		return
+	}
+	userLoc := user.Location()
+
+	// Monthly series repeat on the same date, which every month must have
+	if recurrence.Frequency == models.RecurrenceMonthly && input.StartTime.In(userLoc).Day() > 28 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Monthly series must start on or before the 28th"})
+		return
+	}
+
+	starts := seriesOccurrences(input.StartTime, recurrence.Frequency, recurrence.Interval, recurrence.Count, userLoc)
+	duration := time.Duration(link.Duration) * time.Minute
+
+	// Load availability over the whole series. Calendar busy time is fetched before taking the lock,
+	// so slow calendar APIs don't hold up other bookings
+	first := availability.StartOfDay(starts[0], userLoc)
+	last := availability.StartOfDay(starts[len(starts)-1], userLoc)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	in, _, err := h.loadAvailability(ctx, link, userLoc, first.AddDate(0, 0, -1), last.AddDate(0, 0, 2))
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check time slot availability"})
+		return
+	}
+
+	// Convert answers to string array
+	answers := make(models.StringSlice, 0, len(input.Answers))
+	for question, answer := range input.Answers {
+		answers = append(answers, question+": "+answer)
+	}
+
+	// Check and create the series while holding the advisor's lock, so concurrent bookings can't
+	// take the same slots or uses between the check and the insert
+	series := &models.MeetingSeries{
+		SchedulingLinkID: link.ID,
+		UserID:           link.UserID,
+		ClientEmail:      input.ClientEmail,
+		Frequency:        recurrence.Frequency,
+		Interval:         recurrence.Interval,
+		Occurrences:      len(starts),
+	}
+	meetings := make([]models.Meeting, len(starts))
+	var result *bookingResult
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockAdvisor(tx, link.UserID); err != nil {
+			return err
+		}
+		if result, err = idempotency.replay(tx); err != nil || result != nil {
+			return err
+		}
+
+		// Meetings and uses may have changed since availability was loaded
+		if _, err := loadBookings(tx, link, &in, first.AddDate(0, 0, -1), last.AddDate(0, 0, 2), ""); err != nil {
+			return err
+		}
+
+		// The same invitee can only take one seat of a group session
+		var booked []models.Meeting
+		if err := tx.Where(
+			"scheduling_link_id = ? AND LOWER(TRIM(client_email)) = ? AND status IN ? AND start_time IN ?",
+			link.ID, models.NormalizeEmail(input.ClientEmail), models.LiveMeetingStatuses, starts,
+		).Find(&booked).Error; err != nil {
+			return err
+		}
+		alreadyBooked := map[time.Time]bool{}
+		for _, meeting := range booked {
+			alreadyBooked[meeting.StartTime.UTC()] = true
+		}
+
+		// Check every occurrence, counting the earlier occurrences of the series as booked so they
+		// are held to the link's caps and seats together
+		occurrences := make([]gin.H, len(starts))
+		unavailable := 0
+		for i, start := range starts {
+			explanation := in.Explain(start)
+			occurrence := gin.H{
+				"start_time": start.Format(time.RFC3339),
+				"end_time":   start.Add(duration).Format(time.RFC3339),
+				"available":  explanation.Available,
+			}
+			switch {
+			case !explanation.Available:
+				occurrence["reason"] = explanation.Reason
+				occurrence["error"] = bookingError(explanation.Reason)
+				unavailable++
+			case alreadyBooked[start.UTC()]:
+				occurrence["available"] = false
+				occurrence["error"] = "You have already booked this session"
+				unavailable++
+			default:
+				in.Meetings = append(in.Meetings, availability.Meeting{Start: start, End: start.Add(duration), LinkID: link.ID})
+				in.Uses++
+			}
+			occurrences[i] = occurrence
+		}
+		if unavailable > 0 {
+			result = &bookingResult{status: http.StatusBadRequest, body: gin.H{
+				"error":       fmt.Sprintf("%d of %d occurrences are not available", unavailable, len(starts)),
+				"occurrences": occurrences,
+			}}
+			return nil
+		}
+
+		// Create the series and all of its meetings together
+		client, err := findOrCreateClient(tx, link.UserID, input.bookingInvitee)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(series).Error; err != nil {
+			return err
+		}
+		// A series on a link that requires approval is one request, due when the first occurrence starts
+		deadline := approvalDeadline(link, starts[0])
+		for i, start := range starts {
+			meetings[i] = models.Meeting{
+				SchedulingLinkID: link.ID,
+				UserID:           link.UserID,
+				ClientID:         &client.ID,
+				ClientEmail:      input.ClientEmail,
+				ClientName:       input.ClientName,
+				ClientPhone:      input.ClientPhone,
+				GuestEmails:      guests,
+				LinkedInURL:      input.LinkedInURL,
+				StartTime:        start,
+				EndTime:          start.Add(duration),
+				Answers:          answers,
+				LinkedInData:     "{}",
+				Status:           meetingStatus(deadline),
+				SeriesID:         &series.ID,
+				ApprovalDeadline: deadline,
+			}
+		}
+		if err := tx.Create(&meetings).Error; err != nil {
+			return err
+		}
+		if err := recordMeetingsBooked(tx, models.MeetingActorInvitee, meetings...); err != nil {
+			return err
+		}
+
+		// Each occurrence gets its own manage token, so the invitee can cancel or move them one at a time
+		response := seriesResponse(*series, meetings)
+		for i, occurrence := range response["meetings"].([]gin.H) {
+			token, err := manageToken(meetings[i])
+			if err != nil {
+				return err
+			}
+			occurrence["manage_token"] = token
+		}
+		result = &bookingResult{status: http.StatusCreated, body: response}
+		return idempotency.save(tx, *result)
+	})
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create meeting series"})
+		return
+	}
+	if result.status != http.StatusCreated || series.ID == 0 {
+		c.JSON(result.status, result.body)
+		return
+	}
+
+	// Send one email notification for the whole series
+	unit := "week"
+	if recurrence.Frequency == models.RecurrenceMonthly {
+		unit = "month"
+	}
+	summary := fmt.Sprintf("every %s, %d occurrences until %s", unit, len(starts), starts[len(starts)-1].Format(time.RFC3339))
+	if recurrence.Interval > 1 {
+		summary = fmt.Sprintf("every %d %ss, %d occurrences until %s", recurrence.Interval, unit, len(starts), starts[len(starts)-1].Format(time.RFC3339))
+	}
+
+	meetingDetails := participantDetails(map[string]interface{}{
+		"meeting_id":   meetings[0].ID,
+		"linkedin_url": input.LinkedInURL,
+		"start_time":   meetings[0].StartTime.Format(time.RFC3339),
+		"end_time":     meetings[0].EndTime.Format(time.RFC3339),
+		"answers":      answers,
+		"recurrence":   summary,
+	}, meetings[0])
+	if deadline := meetings[0].ApprovalDeadline; deadline != nil {
+		meetingDetails["approval_deadline"] = deadline.Format(time.RFC3339)
+	}
+	h.notifyNewMeeting(user.Email, meetingDetails)
+	h.notifyBookingConfirmation(meetings, link, user, summary)
+
+	c.JSON(result.status, result.body)
+}
+
+// GetMeetingSeries retrieves one of the authenticated user's series with all of its occurrences
+func (h *SchedulingHandler) GetMeetingSeries(c *gin.Context) {
+	var series models.MeetingSeries
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&series).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting series not found"})
+		return
+	}
+
+	var meetings []models.Meeting
+	if err := h.db.Where("series_id = ?", series.ID).Order("start_time").Find(&meetings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch meetings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, seriesResponse(series, meetings))
+}
+
+// CancelMeeting cancels one of the authenticated user's meetings with an optional reason. Cancelling
+// an occurrence of a series leaves the other occurrences booked.
+func (h *SchedulingHandler) CancelMeeting(c *gin.Context) {
+	meeting, ok := h.loadOwnMeeting(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Reason string `json:"reason" binding:"max=1000"`
+	}
+	// The body is optional, as the reason is
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if meeting.Status == models.MeetingStatusCancelled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This meeting has already been cancelled"})
+		return
+	}
+	if !models.CanTransition(meeting.Status, models.MeetingStatusCancelled) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This meeting can no longer be cancelled"})
+		return
+	}
+
+	// A new nonce revokes the invitee's manage links along with the meeting
+	nonce, err := models.NewManageNonce()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel meeting"})
+		return
+	}
+	now := time.Now()
+	if err := changeMeetingStatus(h.db, &meeting, meetingChange{
+		to:      models.MeetingStatusCancelled,
+		event:   models.MeetingEventCancelled,
+		actor:   models.MeetingActorAdvisor,
+		actorID: advisorActor(c),
+		reason:  input.Reason,
+		updates: map[string]interface{}{"cancelled_at": now, "cancel_reason": input.Reason, "manage_nonce": nonce},
+	}); err != nil {
+		respondStatusChangeError(c, err, "Failed to cancel meeting")
+		return
+	}
+	meeting.CancelledAt = &now
+	meeting.CancelReason = input.Reason
+	h.offerFreedTime(meeting.UserID)
+
+	c.JSON(http.StatusOK, meetingResponse(meeting))
+}
+
+// CancelMeetingSeries cancels every occurrence of a series that has not yet started
+func (h *SchedulingHandler) CancelMeetingSeries(c *gin.Context) {
+	var series models.MeetingSeries
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&series).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meeting series not found"})
+		return
+	}
+
+	now := time.Now()
+	cancelled := 0
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var meetings []models.Meeting
+		if err := tx.Where("series_id = ? AND status IN ? AND start_time > ?", series.ID, []string{models.MeetingStatusScheduled, models.MeetingStatusPending}, now).
+			Find(&meetings).Error; err != nil {
+			return err
+		}
+		for i := range meetings {
+			nonce, err := models.NewManageNonce()
+			if err != nil {
+				return err
+			}
+			if err := changeMeetingStatus(tx, &meetings[i], meetingChange{
+				to:      models.MeetingStatusCancelled,
+				event:   models.MeetingEventCancelled,
+				actor:   models.MeetingActorAdvisor,
+				actorID: advisorActor(c),
+				updates: map[string]interface{}{"cancelled_at": now, "manage_nonce": nonce},
+			}); err != nil {
+				return err
+			}
+			cancelled++
+		}
+		return nil
+	})
+	if err != nil {
+		respondStatusChangeError(c, err, "Failed to cancel meeting series")
+		return
+	}
+	if cancelled > 0 {
+		h.offerFreedTime(series.UserID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Meeting series cancelled successfully",
+		"cancelled": cancelled,
+	})
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	errInvalidTransition = errors.New("invalid meeting status transition")
+	errMeetingChanged    = errors.New("meeting changed since it was loaded")
+)
+
+// meetingChange describes a step in a meeting's lifecycle
+type meetingChange struct {
+	to      string
+	event   string
+	actor   string
+	actorID *uint // the advisor, when they make the change
+	reason  string
+	updates map[string]interface{} // other columns saved together with the status
+}
+
+// changeMeetingStatus moves a meeting through its lifecycle, and is the only place a meeting's
+// status changes after it is booked. It refuses transitions the lifecycle doesn't allow, saves the
+// change only if the meeting still has the status it was loaded with, and records it in the
+// meeting's history.
+func changeMeetingStatus(db *gorm.DB, meeting *models.Meeting, change meetingChange) error {
+	if !models.CanTransition(meeting.Status, change.to) {
+		return errInvalidTransition
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"status": change.to}
+		for column, value := range change.updates {
+			updates[column] = value
+		}
+		result := tx.Model(&models.Meeting{}).Where("id = ? AND status = ?", meeting.ID, meeting.Status).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errMeetingChanged
+		}
+
+		if err := tx.Create(&models.MeetingStatusChange{
+			MeetingID:   meeting.ID,
+			FromStatus:  meeting.Status,
+			ToStatus:    change.to,
+			Event:       change.event,
+			Actor:       change.actor,
+			ActorUserID: change.actorID,
+			Reason:      change.reason,
+		}).Error; err != nil {
+			return err
+		}
+		meeting.Status = change.to
+		return nil
+	})
+}
+
+// recordMeetingsBooked starts the history of newly booked meetings
+func recordMeetingsBooked(tx *gorm.DB, actor string, meetings ...models.Meeting) error {
+	changes := make([]models.MeetingStatusChange, len(meetings))
+	for i, meeting := range meetings {
+		changes[i] = models.MeetingStatusChange{
+			MeetingID: meeting.ID,
+			ToStatus:  meeting.Status,
+			Event:     models.MeetingEventBooked,
+			Actor:     actor,
+		}
+	}
+	return tx.Create(&changes).Error
+}
+
+// respondStatusChangeError writes the response for a status change that failed, with failure as
+// the message for unexpected errors
+func respondStatusChangeError(c *gin.Context, err error, failure string) {
+	if errors.Is(err, errInvalidTransition) || errors.Is(err, errMeetingChanged) {
+		c.JSON(http.StatusConflict, gin.H{"error": "This meeting was changed in the meantime, please reload it"})
+		return
+	}
+	c.Error(err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": failure})
+}
+
+// advisorActor returns the ID of the authenticated advisor to record with a status change
+func advisorActor(c *gin.Context) *uint {
+	userID := c.GetUint("user_id")
+	return &userID
+}
+
+// meetingStatusChangeResponse formats an entry of a meeting's history in snake_case
+func meetingStatusChangeResponse(change models.MeetingStatusChange) gin.H {
+	return gin.H{
+		"from_status":   change.FromStatus,
+		"to_status":     change.ToStatus,
+		"event":         change.Event,
+		"actor":         change.Actor,
+		"actor_user_id": change.ActorUserID,
+		"reason":        change.Reason,
+		"created_at":    change.CreatedAt,
+	}
+}
@@ -0,0 +1,459 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/advisor-scheduling/internal/availability"
+	"github.com/yourusername/advisor-scheduling/internal/models"
+	"gorm.io/gorm"
+)
+
+// waitlistClaimDuration is how long an invitee has to claim a slot offered from the waitlist
+const waitlistClaimDuration = 2 * time.Hour
+
+// waitlistEntryResponse formats a waitlist entry in snake_case
+func waitlistEntryResponse(entry models.WaitlistEntry) gin.H {
+	return gin.H{
+		"id":               entry.ID,
+		"link_id":          entry.SchedulingLinkID,
+		"email":            entry.Email,
+		"date":             entry.Date,
+		"status":           entry.Status,
+		"offered_start":    entry.OfferedStart,
+		"offered_end":      entry.OfferedEnd,
+		"offer_expires_at": entry.OfferExpiresAt,
+		"created_at":       entry.CreatedAt,
+	}
+}
+
+// waitlistPosition counts the entries ahead of and including entry that are still in line
+func (h *SchedulingHandler) waitlistPosition(entry models.WaitlistEntry) (int64, error) {
+	var position int64
+	err := h.db.Model(&models.WaitlistEntry{}).Where(
+		"scheduling_link_id = ? AND status IN ? AND id <= ?",
+		entry.SchedulingLinkID, []string{models.WaitlistStatusWaiting, models.WaitlistStatusOffered}, entry.ID,
+	).Count(&position).Error
+	return position, err
+}
+
+// JoinPublicWaitlist adds an invitee to a link's waitlist without requiring authentication, for a
+// specific date or, without one, the first slot to open on any date. Unlike booking, joining is
+// allowed once the link has reached its maximum uses. The returned token lets the invitee leave.
+func (h *SchedulingHandler) JoinPublicWaitlist(c *gin.Context) {
+	var link models.SchedulingLink
+	if err := h.db.First(&link, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+		return
+	}
+	if !link.IsActive {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This scheduling link is no longer active"})
+		return
+	}
+	if link.ExpiresAt != nil && time.Now().After(*link.ExpiresAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This scheduling link has expired"})
+		return
+	}
+
+	var input struct {
+		Email string  `json:"email" binding:"required,email"`
+		Date  *string `json:"date"` // yyyy-mm-dd in the advisor's time zone, omit for any date
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, link.UserID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user information"})
+		return
+	}
+	userLoc := user.Location()
+
+	if input.Date != nil {
+		day, err := time.ParseInLocation("2006-01-02", *input.Date, userLoc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format (expected: yyyy-mm-dd)"})
+			return
+		}
+		first, last := newAvailabilityInput(link, userLoc).Rules.BookableDates(time.Now(), userLoc)
+		if day.Before(first) || day.After(last) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "This date is outside the link's booking period"})
+			return
+		}
+	}
+
+	// Joining twice keeps the original place in line
+	query := h.db.Where(
+		"scheduling_link_id = ? AND email = ? AND status IN ?",
+		link.ID, strings.ToLower(input.Email), []string{models.WaitlistStatusWaiting, models.WaitlistStatusOffered},
+	)
+	if input.Date != nil {
+		query = query.Where("date = ?", *input.Date)
+	} else {
+		query = query.Where("date IS NULL")
+	}
+	var entry models.WaitlistEntry
+	status := http.StatusOK
+	if err := query.First(&entry).Error; err != nil {
+		token, err := newHoldToken()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join waitlist"})
+			return
+		}
+		entry = models.WaitlistEntry{
+			SchedulingLinkID: link.ID,
+			UserID:           link.UserID,
+			Email:            strings.ToLower(input.Email),
+			Date:             input.Date,
+			Token:            token,
+			Status:           models.WaitlistStatusWaiting,
+		}
+		if err := h.db.Create(&entry).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join waitlist"})
+			return
+		}
+		status = http.StatusCreated
+	}
+
+	position, err := h.waitlistPosition(entry)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join waitlist"})
+		return
+	}
+
+	response := waitlistEntryResponse(entry)
+	response["position"] = position
+	response["token"] = entry.Token
+	c.JSON(status, response)
+}
+
+// LeavePublicWaitlist removes an invitee from a waitlist using the token they got when joining,
+// releasing any slot they were offered
+func (h *SchedulingHandler) LeavePublicWaitlist(c *gin.Context) {
+	var entry models.WaitlistEntry
+	if err := h.db.Where("token = ?", c.Param("token")).First(&entry).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Waitlist entry not found"})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if entry.HoldToken != nil && entry.Status == models.WaitlistStatusOffered {
+			if err := tx.Unscoped().Where("token = ?", *entry.HoldToken).Delete(&models.SlotHold{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(&entry).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to leave waitlist"})
+		return
+	}
+
+	// A released offer goes to the next person in line
+	if entry.Status == models.WaitlistStatusOffered {
+		h.offerFreedTime(entry.UserID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "You have left the waitlist"})
+}
+
+// GetPublicWaitlistClaim shows the slot a waitlist offer holds, so the invitee can book it by
+// passing the claim token as the booking's hold_token before the offer runs out
+func (h *SchedulingHandler) GetPublicWaitlistClaim(c *gin.Context) {
+	var entry models.WaitlistEntry
+	if err := h.db.Where(
+		"hold_token = ? AND status = ? AND offer_expires_at > ?",
+		c.Param("token"), models.WaitlistStatusOffered, time.Now(),
+	).First(&entry).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This offer has expired or was already claimed"})
+		return
+	}
+
+	response := waitlistEntryResponse(entry)
+	response["hold_token"] = entry.HoldToken
+	c.JSON(http.StatusOK, response)
+}
+
+// GetLinkWaitlist lists the waitlist of one of the authenticated user's links in the order
+// people joined
+func (h *SchedulingHandler) GetLinkWaitlist(c *gin.Context) {
+	var link models.SchedulingLink
+	if err := h.db.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("user_id")).First(&link).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+		return
+	}
+
+	var entries []models.WaitlistEntry
+	if err := h.db.Where("scheduling_link_id = ?", link.ID).Order("id").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch waitlist"})
+		return
+	}
+
+	response := make([]gin.H, len(entries))
+	for i, entry := range entries {
+		response[i] = waitlistEntryResponse(entry)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// offerFreedTime offers time freed up on the advisor's calendar, such as by a cancellation, a released
+// hold or new availability, to the waitlists of their links in the background
+func (h *SchedulingHandler) offerFreedTime(userID uint) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		var links []models.SchedulingLink
+		if err := h.db.Where(
+			"user_id = ? AND id IN (?)",
+			userID, h.db.Model(&models.WaitlistEntry{}).Select("scheduling_link_id").Where("status = ?", models.WaitlistStatusWaiting),
+		).Find(&links).Error; err != nil {
+			fmt.Printf("Failed to fetch waitlisted links: %v\n", err)
+			return
+		}
+		for _, link := range links {
+			if err := h.offerWaitlistSlots(ctx, link); err != nil {
+				fmt.Printf("Failed to offer slots to the waitlist of link %d: %v\n", link.ID, err)
+			}
+		}
+	}()
+}
+
+// ProcessWaitlists expires offers that weren't claimed in time, then offers the slots they held to
+// the rest of the waitlists of those advisors. It is run periodically by the server. Time freed in
+// other ways is offered as soon as it is freed, so only advisors with lapsed offers are reloaded.
+// Links that fail are skipped, and their errors returned together once the rest have been processed.
+func (h *SchedulingHandler) ProcessWaitlists(ctx context.Context) error {
+	// Lapsed offers lose their place; their holds have expired at the same time
+	var lapsed []models.WaitlistEntry
+	if err := h.db.Where("status = ? AND offer_expires_at <= ?", models.WaitlistStatusOffered, time.Now()).Find(&lapsed).Error; err != nil {
+		return fmt.Errorf("failed to fetch lapsed waitlist offers: %v", err)
+	}
+	if len(lapsed) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(lapsed))
+	userIDs := make([]uint, len(lapsed))
+	for i, entry := range lapsed {
+		ids[i] = entry.ID
+		userIDs[i] = entry.UserID
+	}
+	if err := h.db.Model(&models.WaitlistEntry{}).
+		Where("id IN ? AND status = ?", ids, models.WaitlistStatusOffered).
+		Update("status", models.WaitlistStatusExpired).Error; err != nil {
+		return fmt.Errorf("failed to expire waitlist offers: %v", err)
+	}
+
+	var links []models.SchedulingLink
+	if err := h.db.Where(
+		"user_id IN ? AND id IN (?)",
+		userIDs, h.db.Model(&models.WaitlistEntry{}).Select("scheduling_link_id").Where("status = ?", models.WaitlistStatusWaiting),
+	).Find(&links).Error; err != nil {
+		return fmt.Errorf("failed to fetch waitlisted links: %v", err)
+	}
+	// One link failing doesn't hold up the others
+	var errs []error
+	for _, link := range links {
+		if err := h.offerWaitlistSlots(ctx, link); err != nil {
+			fmt.Printf("Failed to offer slots to the waitlist of link %d: %v\n", link.ID, err)
+			errs = append(errs, fmt.Errorf("failed to offer slots to the waitlist of link %d: %v", link.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// offerWaitlistSlots offers the link's open slots to its waitlist in the order people joined. Each
+// offer places a slot hold, so the slot is kept for the invitee until they claim it with the hold
+// token or the offer runs out.
+func (h *SchedulingHandler) offerWaitlistSlots(ctx context.Context, link models.SchedulingLink) error {
+	var user models.User
+	if err := h.db.First(&user, link.UserID).Error; err != nil {
+		return err
+	}
+	userLoc := user.Location()
+
+	// Entries for dates that have passed can no longer be offered anything
+	today := time.Now().In(userLoc).Format("2006-01-02")
+	if err := h.db.Model(&models.WaitlistEntry{}).
+		Where("scheduling_link_id = ? AND status = ? AND date < ?", link.ID, models.WaitlistStatusWaiting, today).
+		Update("status", models.WaitlistStatusExpired).Error; err != nil {
+		return err
+	}
+
+	first, last := newAvailabilityInput(link, userLoc).Rules.BookableDates(time.Now(), userLoc)
+	if last.Before(first) {
+		return nil
+	}
+
+	// Only load the dates the waitlist is waiting for, unless someone will take any date
+	var dates []*string
+	if err := h.db.Model(&models.WaitlistEntry{}).
+		Where("scheduling_link_id = ? AND status = ?", link.ID, models.WaitlistStatusWaiting).
+		Pluck("date", &dates).Error; err != nil {
+		return err
+	}
+	if len(dates) == 0 {
+		return nil
+	}
+	first, last = waitlistDateRange(dates, first, last, userLoc)
+	if last.Before(first) {
+		return nil
+	}
+
+	in, _, err := h.loadAvailability(ctx, link, userLoc, first.AddDate(0, 0, -1), last.AddDate(0, 0, 2))
+	if err != nil {
+		return err
+	}
+
+	var offered []models.WaitlistEntry
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		// Offers are checked and placed under the same lock as bookings and holds
+		if _, err := lockAdvisor(tx, link.UserID); err != nil {
+			return err
+		}
+		if _, err := loadBookings(tx, link, &in, first.AddDate(0, 0, -1), last.AddDate(0, 0, 2), ""); err != nil {
+			return err
+		}
+		in.Now = time.Now()
+
+		var waiting []models.WaitlistEntry
+		if err := tx.Where("scheduling_link_id = ? AND status = ?", link.ID, models.WaitlistStatusWaiting).Order("id").Find(&waiting).Error; err != nil {
+			return err
+		}
+
+		for _, entry := range waiting {
+			slot, ok := firstWaitlistSlot(in, entry, first, last)
+			if !ok {
+				continue
+			}
+
+			token, err := newHoldToken()
+			if err != nil {
+				return err
+			}
+			expiresAt := in.Now.Add(waitlistClaimDuration)
+			hold := &models.SlotHold{
+				SchedulingLinkID: link.ID,
+				UserID:           link.UserID,
+				Token:            token,
+				StartTime:        slot.Start,
+				EndTime:          slot.End,
+				ExpiresAt:        expiresAt,
+			}
+			if err := tx.Create(hold).Error; err != nil {
+				return err
+			}
+
+			entry.Status = models.WaitlistStatusOffered
+			entry.HoldToken = &token
+			entry.OfferedStart = &slot.Start
+			entry.OfferedEnd = &slot.End
+			entry.OfferExpiresAt = &expiresAt
+			if err := tx.Model(&entry).Updates(map[string]interface{}{
+				"status":           entry.Status,
+				"hold_token":       entry.HoldToken,
+				"offered_start":    entry.OfferedStart,
+				"offered_end":      entry.OfferedEnd,
+				"offer_expires_at": entry.OfferExpiresAt,
+			}).Error; err != nil {
+				return err
+			}
+			offered = append(offered, entry)
+
+			// The held slot is no longer open to the people behind in line
+			in.Meetings = append(in.Meetings, availability.Meeting{Start: slot.Start, End: slot.End, LinkID: link.ID, Held: true})
+			in.Uses++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range offered {
+		h.notifyWaitlistOffer(link, entry)
+	}
+	return nil
+}
+
+// waitlistDateRange narrows the bookable dates first to last down to the dates waitlist entries are
+// waiting for. An entry waiting for any date keeps the whole range.
+func waitlistDateRange(dates []*string, first, last time.Time, loc *time.Location) (time.Time, time.Time) {
+	var earliest, latest time.Time
+	for _, date := range dates {
+		if date == nil {
+			return first, last
+		}
+		day, err := time.ParseInLocation("2006-01-02", *date, loc)
+		if err != nil {
+			continue
+		}
+		if earliest.IsZero() || day.Before(earliest) {
+			earliest = day
+		}
+		if latest.IsZero() || day.After(latest) {
+			latest = day
+		}
+	}
+	if earliest.IsZero() {
+		return first, first.AddDate(0, 0, -1)
+	}
+	if earliest.After(first) {
+		first = earliest
+	}
+	if latest.Before(last) {
+		last = latest
+	}
+	return first, last
+}
+
+// firstWaitlistSlot finds the earliest open slot for a waitlist entry, on its date if it has one
+func firstWaitlistSlot(in availability.Input, entry models.WaitlistEntry, first, last time.Time) (availability.Slot, bool) {
+	if entry.Date != nil {
+		day, err := time.ParseInLocation("2006-01-02", *entry.Date, in.Location)
+		if err != nil || day.Before(first) || day.After(last) {
+			return availability.Slot{}, false
+		}
+		first, last = day, day
+	}
+
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if slots := in.Day(day).Slots; len(slots) > 0 {
+			return slots[0], true
+		}
+	}
+	return availability.Slot{}, false
+}
+
+// notifyWaitlistOffer emails a waitlisted invitee the slot they are offered and the link to claim
+// it. Nothing is sent if the handler has no email service.
+func (h *SchedulingHandler) notifyWaitlistOffer(link models.SchedulingLink, entry models.WaitlistEntry) {
+	if h.emailService == nil {
+		return
+	}
+
+	details := map[string]interface{}{
+		"title":      link.Title,
+		"start_time": entry.OfferedStart.Format(time.RFC3339),
+		"end_time":   entry.OfferedEnd.Format(time.RFC3339),
+		"expires_at": entry.OfferExpiresAt.Format(time.RFC3339),
+		"claim_url":  fmt.Sprintf("%s/schedule/%d?claim=%s", os.Getenv("FRONTEND_URL"), link.ID, *entry.HoldToken),
+	}
+	go func() {
+		emailCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		if err := h.emailService.SendWaitlistOffer(emailCtx, entry.Email, details); err != nil {
+			// Log the error, the offer itself has been saved
+			fmt.Printf("Failed to send email notification: %v\n", err)
+		}
+	}()
+}
@@ -0,0 +1,186 @@
+// Package holidays reads all-day holidays from ICS calendars, either uploaded by an advisor or from
+// the country datasets bundled with the server.
+package holidays
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed data/*.ics
+var data embed.FS
+
+// Holiday is an all-day event covering StartDate to EndDate inclusive, both yyyy-mm-dd
+type Holiday struct {
+	StartDate string
+	EndDate   string
+	Name      string
+}
+
+// Dataset describes a bundled holiday calendar
+type Dataset struct {
+	Country string // ISO 3166-1 alpha-2 code
+	Name    string
+}
+
+// Datasets lists the bundled holiday calendars
+var Datasets = []Dataset{
+	{Country: "GB", Name: "England and Wales bank holidays"},
+	{Country: "US", Name: "United States federal holidays"},
+}
+
+// maxHolidayDays limits how long a single all-day event may be, so a stray multi-month event
+// in an uploaded calendar doesn't block a whole season
+const maxHolidayDays = 31
+
+// LookupDataset returns the bundled dataset for a country code
+func LookupDataset(country string) (Dataset, bool) {
+	for _, dataset := range Datasets {
+		if strings.EqualFold(dataset.Country, country) {
+			return dataset, true
+		}
+	}
+	return Dataset{}, false
+}
+
+// Bundled returns the holidays of a bundled dataset
+func Bundled(country string) ([]Holiday, error) {
+	dataset, ok := LookupDataset(country)
+	if !ok {
+		return nil, fmt.Errorf("no holiday dataset for %q", country)
+	}
+	file, err := data.Open("data/" + dataset.Country + ".ics")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseICS(file)
+}
+
+// ParseICS reads the all-day events of an ICS calendar in date order. Events with a time of day
+// are skipped, since only whole days are treated as holidays.
+func ParseICS(r io.Reader) ([]Holiday, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Holiday{}
+	inEvent := false
+	var start, end, summary string
+	for _, line := range lines {
+		name, params, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent = true
+			start, end, summary = "", "", ""
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			inEvent = false
+			holiday, ok, err := newHoliday(start, end, summary)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				result = append(result, holiday)
+			}
+		case !inEvent:
+		case name == "DTSTART":
+			start = dateValue(params, value)
+		case name == "DTEND":
+			end = dateValue(params, value)
+		case name == "SUMMARY":
+			summary = unescape(value)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartDate < result[j].StartDate
+	})
+	return result, nil
+}
+
+// newHoliday builds a holiday from the DTSTART, DTEND and SUMMARY of an event, reporting false if
+// the event is not all-day. DTEND is exclusive in ICS, so it is moved back a day.
+func newHoliday(start, end, summary string) (Holiday, bool, error) {
+	if start == "" {
+		return Holiday{}, false, nil
+	}
+	startDate, err := time.Parse("20060102", start)
+	if err != nil {
+		return Holiday{}, false, fmt.Errorf("invalid DTSTART %q", start)
+	}
+	endDate := startDate
+	if end != "" {
+		exclusive, err := time.Parse("20060102", end)
+		if err != nil {
+			return Holiday{}, false, fmt.Errorf("invalid DTEND %q", end)
+		}
+		if exclusive.After(startDate) {
+			endDate = exclusive.AddDate(0, 0, -1)
+		}
+	}
+	if endDate.Sub(startDate) >= maxHolidayDays*24*time.Hour {
+		return Holiday{}, false, nil
+	}
+	if summary == "" {
+		summary = "Holiday"
+	}
+	return Holiday{
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Name:      summary,
+	}, true, nil
+}
+
+// unfold splits an ICS file into content lines, joining lines continued with leading whitespace
+func unfold(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// splitProperty splits a content line such as DTSTART;VALUE=DATE:20260101 into its upper-cased
+// name, its parameters and its value
+func splitProperty(line string) (string, string, string) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return strings.ToUpper(line), "", ""
+	}
+	name, value := line[:colon], line[colon+1:]
+	params := ""
+	if semicolon := strings.Index(name, ";"); semicolon >= 0 {
+		name, params = name[:semicolon], name[semicolon+1:]
+	}
+	return strings.ToUpper(name), strings.ToUpper(params), value
+}
+
+// dateValue returns the yyyymmdd value of an all-day DTSTART or DTEND, or an empty string if the
+// property has a time of day
+func dateValue(params, value string) string {
+	if strings.Contains(params, "VALUE=DATE-TIME") || strings.Contains(value, "T") || len(value) != 8 {
+		return ""
+	}
+	return value
+}
+
+// unescape resolves the backslash escapes of ICS text values
+func unescape(value string) string {
+	replacer := strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return strings.TrimSpace(replacer.Replace(value))
+}
@@ -0,0 +1,159 @@
+package holidays
+
+import (
+	"strings"
+	"testing"
+)
+
+// calendar wraps events in a VCALENDAR with CRLF line endings, as ICS files are written
+func calendar(events ...string) string {
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0"}
+	for _, event := range events {
+		lines = append(lines, "BEGIN:VEVENT", event, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR", "")
+	return strings.Join(lines, "\r\n")
+}
+
+func TestParseICS(t *testing.T) {
+	tests := []struct {
+		name     string
+		ics      string
+		holidays []Holiday
+	}{
+		{
+			"all-day event",
+			calendar("DTSTART;VALUE=DATE:20260101\r\nDTEND;VALUE=DATE:20260102\r\nSUMMARY:New Year's Day"),
+			[]Holiday{{StartDate: "2026-01-01", EndDate: "2026-01-01", Name: "New Year's Day"}},
+		},
+		{
+			"date without VALUE=DATE",
+			calendar("DTSTART:20260704\r\nDTEND:20260705\r\nSUMMARY:Independence Day"),
+			[]Holiday{{StartDate: "2026-07-04", EndDate: "2026-07-04", Name: "Independence Day"}},
+		},
+		{
+			"event with a time of day is skipped",
+			calendar(
+				"DTSTART:20260105T090000Z\r\nDTEND:20260105T100000Z\r\nSUMMARY:Standup",
+				"DTSTART;VALUE=DATE-TIME:20260106T090000\r\nSUMMARY:Review",
+				"DTSTART;VALUE=DATE:20260107\r\nSUMMARY:Day off",
+			),
+			[]Holiday{{StartDate: "2026-01-07", EndDate: "2026-01-07", Name: "Day off"}},
+		},
+		{
+			"missing DTEND lasts one day",
+			calendar("DTSTART;VALUE=DATE:20261225\r\nSUMMARY:Christmas Day"),
+			[]Holiday{{StartDate: "2026-12-25", EndDate: "2026-12-25", Name: "Christmas Day"}},
+		},
+		{
+			"DTEND on the start date lasts one day",
+			calendar("DTSTART;VALUE=DATE:20261225\r\nDTEND;VALUE=DATE:20261225\r\nSUMMARY:Christmas Day"),
+			[]Holiday{{StartDate: "2026-12-25", EndDate: "2026-12-25", Name: "Christmas Day"}},
+		},
+		{
+			"multi-day event ends the day before DTEND",
+			calendar("DTSTART;VALUE=DATE:20261224\r\nDTEND;VALUE=DATE:20261227\r\nSUMMARY:Office closed"),
+			[]Holiday{{StartDate: "2026-12-24", EndDate: "2026-12-26", Name: "Office closed"}},
+		},
+		{
+			"event longer than a month is skipped",
+			calendar("DTSTART;VALUE=DATE:20260601\r\nDTEND;VALUE=DATE:20260901\r\nSUMMARY:Summer"),
+			[]Holiday{},
+		},
+		{
+			"folded lines are joined",
+			calendar("DTSTART;VALUE=DATE:20260525\r\nSUMMARY:Spring bank\r\n  holi\r\n\tday"),
+			[]Holiday{{StartDate: "2026-05-25", EndDate: "2026-05-25", Name: "Spring bank holiday"}},
+		},
+		{
+			"escaped text and a missing summary",
+			calendar(
+				"DTSTART;VALUE=DATE:20260102\r\nSUMMARY:Closed\\, all offices\\; see\\nintranet",
+				"DTSTART;VALUE=DATE:20260103",
+			),
+			[]Holiday{
+				{StartDate: "2026-01-02", EndDate: "2026-01-02", Name: "Closed, all offices; see intranet"},
+				{StartDate: "2026-01-03", EndDate: "2026-01-03", Name: "Holiday"},
+			},
+		},
+		{
+			"sorted by date, ignoring properties outside events",
+			"BEGIN:VCALENDAR\nDTSTART;VALUE=DATE:20260301\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20260210\nSUMMARY:Later\nEND:VEVENT\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20260120\nSUMMARY:Earlier\nEND:VEVENT\nEND:VCALENDAR\n",
+			[]Holiday{
+				{StartDate: "2026-01-20", EndDate: "2026-01-20", Name: "Earlier"},
+				{StartDate: "2026-02-10", EndDate: "2026-02-10", Name: "Later"},
+			},
+		},
+		{
+			"empty file",
+			"",
+			[]Holiday{},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseICS(strings.NewReader(tt.ics))
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if len(got) != len(tt.holidays) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.holidays)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.holidays[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got[i], tt.holidays[i])
+			}
+		}
+	}
+}
+
+func TestParseICSRejectsMalformedDates(t *testing.T) {
+	tests := []struct {
+		name string
+		ics  string
+	}{
+		{"invalid DTSTART", calendar("DTSTART;VALUE=DATE:20261345\r\nSUMMARY:Bad")},
+		{"invalid DTEND", calendar("DTSTART;VALUE=DATE:20260101\r\nDTEND;VALUE=DATE:2026ab02\r\nSUMMARY:Bad")},
+	}
+	for _, tt := range tests {
+		if got, err := ParseICS(strings.NewReader(tt.ics)); err == nil {
+			t.Errorf("%s: got %v, want an error", tt.name, got)
+		}
+	}
+}
+
+func TestBundled(t *testing.T) {
+	tests := []struct {
+		country string
+		count   int
+		first   Holiday
+		last    Holiday
+	}{
+		{"US", 33, Holiday{"2026-01-01", "2026-01-01", "New Year's Day"}, Holiday{"2028-12-25", "2028-12-25", "Christmas Day"}},
+		{"gb", 24, Holiday{"2026-01-01", "2026-01-01", "New Year's Day"}, Holiday{"2028-12-26", "2028-12-26", "Boxing Day"}},
+	}
+	for _, tt := range tests {
+		got, err := Bundled(tt.country)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.country, err)
+			continue
+		}
+		if len(got) != tt.count {
+			t.Errorf("%s: got %d holidays, want %d", tt.country, len(got), tt.count)
+			continue
+		}
+		if got[0] != tt.first || got[len(got)-1] != tt.last {
+			t.Errorf("%s: got holidays from %v to %v, want %v to %v", tt.country, got[0], got[len(got)-1], tt.first, tt.last)
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i].StartDate < got[i-1].StartDate {
+				t.Errorf("%s: %v comes after %v", tt.country, got[i], got[i-1])
+			}
+		}
+	}
+
+	if _, err := Bundled("FR"); err == nil {
+		t.Error("FR: got a dataset, want an error")
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow counts one client's requests in the current window
+type rateWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// RateLimit allows each client IP at most limit requests per window, answering the rest with
+// 429 Too Many Requests until their window ends
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	clients := map[string]*rateWindow{}
+	nextSweep := time.Now().Add(window)
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		ip := c.ClientIP()
+
+		mu.Lock()
+		// Forget clients whose window has ended, so the map doesn't grow without bound
+		if now.After(nextSweep) {
+			for key, w := range clients {
+				if now.After(w.resetAt) {
+					delete(clients, key)
+				}
+			}
+			nextSweep = now.Add(window)
+		}
+		w, ok := clients[ip]
+		if !ok || now.After(w.resetAt) {
+			w = &rateWindow{resetAt: now.Add(window)}
+			clients[ip] = w
+		}
+		w.count++
+		allowed, retryAfter := w.count <= limit, w.resetAt.Sub(now)
+		mu.Unlock()
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, please try again later"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Client is a person who books meetings with an advisor. Clients are keyed by their normalized
+// email, so every meeting a repeat visitor books, through any of the advisor's links, links to the
+// same client.
+type Client struct {
+	gorm.Model
+	UserID           uint   `gorm:"not null;uniqueIndex:idx_clients_user_email"`
+	Email            string `gorm:"type:varchar(255);not null;uniqueIndex:idx_clients_user_email"` // see NormalizeEmail
+	Name             string
+	Phone            string `gorm:"type:varchar(32)"`
+	LinkedInURL      string `gorm:"column:linkedin_url"`
+	HubspotContactID *string
+	LinkedInData     string `gorm:"column:linkedin_data;type:json"` // the LinkedIn profile found for the client
+}
+
+// NormalizeEmail returns the form of an email address clients are keyed by
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
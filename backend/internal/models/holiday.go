@@ -0,0 +1,31 @@
+package models
+
+import "gorm.io/gorm"
+
+// HolidayCalendar is a set of holidays imported by a user, either from a bundled country dataset
+// or from an uploaded ICS file. Its holidays are unavailable in slot computation.
+type HolidayCalendar struct {
+	gorm.Model
+	UserID     uint      `gorm:"not null;index"`
+	ScheduleID *uint     `gorm:"index"` // nil means every one of the user's schedules
+	Name       string    `gorm:"type:varchar(255);not null"`
+	Source     string    `gorm:"type:varchar(16);not null"` // "bundled" or "ics"
+	Country    string    `gorm:"type:varchar(2)"`           // dataset country code for bundled calendars
+	Holidays   []Holiday `gorm:"foreignKey:HolidayCalendarID"`
+}
+
+// Holiday sources
+const (
+	HolidaySourceBundled = "bundled"
+	HolidaySourceICS     = "ics"
+)
+
+// Holiday is an all-day holiday from an imported calendar
+type Holiday struct {
+	gorm.Model
+	HolidayCalendarID uint   `gorm:"not null;index"`
+	UserID            uint   `gorm:"not null;index"`
+	StartDate         string `gorm:"type:varchar(10);not null"` // yyyy-mm-dd, inclusive
+	EndDate           string `gorm:"type:varchar(10);not null"` // yyyy-mm-dd, inclusive
+	Name              string `gorm:"type:varchar(255);not null"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "gorm.io/gorm"
+
+// IdempotencyKey stores the result of a request sent with an Idempotency-Key header, so a retry of
+// the same request gets the original result instead of repeating it
+type IdempotencyKey struct {
+	gorm.Model
+	Scope       string `gorm:"type:varchar(64);not null;uniqueIndex:idx_idempotency_keys_scope_key"` // the endpoint and resource, e.g. "meeting:12"
+	Key         string `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_scope_key"`
+	RequestHash string `gorm:"type:char(64);not null"` // SHA-256 of the request body
+	StatusCode  int    `gorm:"not null"`
+	Response    string `gorm:"type:json;not null"`
+}
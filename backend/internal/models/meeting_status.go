@@ -0,0 +1,83 @@
+package models
+
+import "gorm.io/gorm"
+
+// Meeting statuses
+const (
+	MeetingStatusPending   = "pending" // waiting for the advisor to accept it
+	MeetingStatusScheduled = "scheduled"
+	MeetingStatusCompleted = "completed"
+	MeetingStatusNoShow    = "no_show"
+	MeetingStatusCancelled = "cancelled"
+	MeetingStatusDeclined  = "declined"
+	MeetingStatusExpired   = "expired" // pending past its approval deadline
+)
+
+// LiveMeetingStatuses are the statuses of meetings that hold their slot and count as a use of
+// their link
+var LiveMeetingStatuses = []string{MeetingStatusPending, MeetingStatusScheduled, MeetingStatusCompleted, MeetingStatusNoShow}
+
+// ReleasedMeetingStatuses are the statuses of meetings that no longer hold their slot. They are final.
+var ReleasedMeetingStatuses = []string{MeetingStatusCancelled, MeetingStatusDeclined, MeetingStatusExpired}
+
+// meetingTransitions lists the statuses a meeting may move to from each status. A meeting keeps
+// its status when it is rescheduled, and completed and no-show meetings may be corrected into
+// each other after the fact.
+var meetingTransitions = map[string][]string{
+	MeetingStatusPending:   {MeetingStatusPending, MeetingStatusScheduled, MeetingStatusDeclined, MeetingStatusExpired, MeetingStatusCancelled},
+	MeetingStatusScheduled: {MeetingStatusScheduled, MeetingStatusCompleted, MeetingStatusNoShow, MeetingStatusCancelled},
+	MeetingStatusCompleted: {MeetingStatusNoShow},
+	MeetingStatusNoShow:    {MeetingStatusCompleted},
+}
+
+// CanTransition reports whether a meeting may move from one status to another
+func CanTransition(from, to string) bool {
+	for _, status := range meetingTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Released reports whether the meeting no longer takes up its slot or a use of its link
+func (m Meeting) Released() bool {
+	for _, status := range ReleasedMeetingStatuses {
+		if m.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Meeting events, recorded with each status change
+const (
+	MeetingEventBooked      = "booked"
+	MeetingEventAccepted    = "accepted"
+	MeetingEventDeclined    = "declined"
+	MeetingEventExpired     = "expired"
+	MeetingEventRescheduled = "rescheduled"
+	MeetingEventCancelled   = "cancelled"
+	MeetingEventCompleted   = "completed"
+	MeetingEventNoShow      = "no_show"
+)
+
+// Actors that change a meeting's status
+const (
+	MeetingActorAdvisor = "advisor"
+	MeetingActorInvitee = "invitee"
+	MeetingActorSystem  = "system" // scheduled jobs such as expiring pending bookings
+)
+
+// MeetingStatusChange records one transition in a meeting's lifecycle, including reschedules that
+// keep the status. Its CreatedAt is when the change happened.
+type MeetingStatusChange struct {
+	gorm.Model
+	MeetingID   uint   `gorm:"not null;index"`
+	FromStatus  string `gorm:"type:varchar(16)"` // empty when the meeting was booked
+	ToStatus    string `gorm:"type:varchar(16);not null"`
+	Event       string `gorm:"type:varchar(16);not null"`
+	Actor       string `gorm:"type:varchar(16);not null"` // advisor, invitee or system
+	ActorUserID *uint  // the advisor, when they made the change
+	Reason      string `gorm:"type:text"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"time"
 
 	"gorm.io/gorm"
@@ -20,13 +22,16 @@ type User struct {
 	CalendarIDs    []string  `json:"calendar_ids" gorm:"type:json"`
 	LastLoginAt    time.Time `json:"last_login_at"`
 	IsActive       bool      `json:"is_active" gorm:"default:true"`
+	Timezone       string    `json:"timezone" gorm:"type:varchar(64);default:'UTC'"` // IANA name, e.g. America/New_York
 	
 	// Relationships
-	GoogleAccounts    []GoogleAccount    `json:"google_accounts" gorm:"foreignKey:UserID"`
-	HubspotAccounts   []HubSpotAccount   `json:"hubspot_accounts" gorm:"foreignKey:UserID"`
-	SchedulingWindows []SchedulingWindow `json:"scheduling_windows" gorm:"foreignKey:UserID"`
-	SchedulingLinks   []SchedulingLink   `json:"scheduling_links" gorm:"foreignKey:UserID"`
-	Meetings          []Meeting          `json:"meetings" gorm:"foreignKey:UserID"`
+	GoogleAccounts      []GoogleAccount      `json:"google_accounts" gorm:"foreignKey:UserID"`
+	HubspotAccounts     []HubSpotAccount     `json:"hubspot_accounts" gorm:"foreignKey:UserID"`
+	Schedules           []Schedule           `json:"schedules" gorm:"foreignKey:UserID"`
+	SchedulingWindows   []SchedulingWindow   `json:"scheduling_windows" gorm:"foreignKey:UserID"`
+	SchedulingOverrides []SchedulingOverride `json:"scheduling_overrides" gorm:"foreignKey:UserID"`
+	SchedulingLinks     []SchedulingLink     `json:"scheduling_links" gorm:"foreignKey:UserID"`
+	Meetings            []Meeting            `json:"meetings" gorm:"foreignKey:UserID"`
 }
 
 // TableName specifies the table name for the User model
@@ -34,23 +39,97 @@ func (User) TableName() string {
 	return "users"
 }
 
+// Location returns the user's time zone, falling back to UTC if it is unset or unknown
+func (u User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Schedule is a named set of weekly windows and date overrides, such as "Summer hours".
+// Each user has one default schedule, which links without a schedule of their own use.
+type Schedule struct {
+	gorm.Model
+	UserID        uint      `gorm:"not null;index"`
+	Name          string    `gorm:"type:varchar(100);not null"`
+	IsDefault     bool      `gorm:"not null;default:false"`
+}
+
 type SchedulingWindow struct {
 	gorm.Model
 	UserID        uint      `gorm:"not null"`
+	ScheduleID    *uint     `gorm:"index"` // nil means the user's default schedule
 	StartHour     int       `gorm:"not null"`
+	StartMinute   int       `gorm:"not null;default:0"`
 	EndHour       int       `gorm:"not null"`
+	EndMinute     int       `gorm:"not null;default:0"`
 	Weekday       int       `gorm:"not null"` // 0-6 (Sunday-Saturday)
+	Timezone      string    `gorm:"type:varchar(64)"` // empty means the user's time zone
 	IsActive      bool      `gorm:"default:true"`
 }
 
+// Location returns the window's own time zone, or fallback if the window does not set one
+func (w SchedulingWindow) Location(fallback *time.Location) *time.Location {
+	if w.Timezone == "" {
+		return fallback
+	}
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return fallback
+	}
+	return loc
+}
+
+// SchedulingOverride changes availability on specific dates in the user's time zone.
+// Available overrides replace the weekly windows for the dates they cover, while
+// unavailable overrides block time on top of whatever windows apply.
+type SchedulingOverride struct {
+	gorm.Model
+	UserID        uint      `gorm:"not null;index"`
+	ScheduleID    *uint     `gorm:"index"` // nil means the user's default schedule
+	StartDate     string    `gorm:"type:varchar(10);not null"` // yyyy-mm-dd, inclusive
+	EndDate       string    `gorm:"type:varchar(10);not null"` // yyyy-mm-dd, inclusive
+	StartMinute   *int      // minutes after midnight; nil together with EndMinute means the whole day
+	EndMinute     *int
+	IsAvailable   bool      `gorm:"not null"` // true adds hours, false blocks them
+	Reason        string
+}
+
+// AllDay reports whether the override covers whole days rather than a time range
+func (o SchedulingOverride) AllDay() bool {
+	return o.StartMinute == nil || o.EndMinute == nil
+}
+
 type SchedulingLink struct {
 	gorm.Model
 	UserID            uint      `gorm:"not null"`
+	ScheduleID        *uint     // nil means the user's default schedule
 	Title             string    `gorm:"not null"`
 	Duration          int       `gorm:"not null"` // in minutes
+	Seats             int       `gorm:"not null;default:1"` // attendees per slot, more than 1 for group sessions
 	MaxUses           *int      // nil means unlimited
 	ExpiresAt         *time.Time
-	MaxDaysInAdvance  int       `gorm:"not null"`
+	HorizonMode       string    `gorm:"type:varchar(16);not null;default:'rolling'"` // rolling, business_days or fixed
+	MaxDaysInAdvance  int       `gorm:"not null"` // days ahead for the rolling and business_days modes
+	StartDate         *string   `gorm:"type:varchar(10)"` // yyyy-mm-dd first bookable date for the fixed mode
+	EndDate           *string   `gorm:"type:varchar(10)"` // yyyy-mm-dd last bookable date for the fixed mode
+	BufferBefore      int       `gorm:"not null;default:0"` // minutes kept free before each meeting
+	BufferAfter       int       `gorm:"not null;default:0"` // minutes kept free after each meeting
+	MinNotice         int       `gorm:"not null;default:0"` // minutes between booking and meeting start
+	SlotInterval      int       `gorm:"not null;default:0"` // minutes between slot starts; 0 means the meeting duration
+	TopOfHourOnly     bool      `gorm:"default:false"`      // only offer slots starting on the hour
+	MaxPerDay         *int      // nil means unlimited
+	MaxPerWeek        *int      // nil means unlimited
+	MaxOccurrences    int       `gorm:"not null;default:0"` // longest recurring series an invitee may book; below 2 disables series
+	SlotRanking       string    `gorm:"type:varchar(16);not null;default:'none'"` // none, compact or compact_only
+	RequiresApproval  bool      `gorm:"default:false"` // new bookings are pending until the advisor accepts them
+	ApprovalHours     int       `gorm:"not null;default:24"` // hours the advisor has to accept a pending booking before it expires
+	MaxGuests         int       `gorm:"not null;default:0"` // additional guest emails an invitee may add to a booking
 	CustomQuestions   string    `gorm:"type:json"` // Store as JSON string
 	IsActive          bool      `gorm:"default:true"`
 }
@@ -59,7 +138,11 @@ type Meeting struct {
 	gorm.Model
 	SchedulingLinkID  uint      `gorm:"not null"`
 	UserID            uint      `gorm:"not null"`
+	ClientID          *uint     `gorm:"index"` // the client the invitee's email belongs to
 	ClientEmail       string    `gorm:"not null"`
+	ClientName        string
+	ClientPhone       string    `gorm:"type:varchar(32)"`
+	GuestEmails       StringSlice `gorm:"type:json"` // others attending with the invitee
 	LinkedInURL       string
 	StartTime         time.Time `gorm:"not null"`
 	EndTime           time.Time `gorm:"not null"`
@@ -67,4 +150,73 @@ type Meeting struct {
 	HubspotContactID  *string
 	LinkedInData      string    `gorm:"type:json"`
 	ContextNotes      string    `gorm:"type:text"`
+	Status            string    `gorm:"type:varchar(16);not null;default:'scheduled'"` // see meeting_status.go for the lifecycle
+	SeriesID          *uint     `gorm:"index"` // set for occurrences of a recurring series
+	CancelledAt       *time.Time
+	CancelReason      string    `gorm:"type:text"` // given by the invitee when they cancel
+	RescheduledAt     *time.Time // last time the invitee moved the meeting
+	ApprovalDeadline  *time.Time `gorm:"index"` // when a pending booking expires unless the advisor accepts it
+	DeclineReason     string    `gorm:"type:text"` // given by the advisor when they decline a pending booking
+	ManageNonce       string    `gorm:"type:varchar(32)" json:"-"` // signed into the invitee's manage tokens, changing it revokes them
+}
+
+// BeforeCreate gives a new meeting the nonce its manage tokens are issued for
+func (m *Meeting) BeforeCreate(tx *gorm.DB) error {
+	if m.ManageNonce != "" {
+		return nil
+	}
+	nonce, err := NewManageNonce()
+	if err != nil {
+		return err
+	}
+	m.ManageNonce = nonce
+	return nil
+}
+
+// NewManageNonce returns a random nonce for a meeting's manage tokens
+func NewManageNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// SlotHold reserves a slot for a few minutes while an invitee fills in the booking form. Expired
+// holds are ignored, and cleaned up as new holds are placed.
+type SlotHold struct {
+	gorm.Model
+	SchedulingLinkID uint      `gorm:"not null;index"`
+	UserID           uint      `gorm:"not null;index"`
+	Token            string    `gorm:"type:varchar(64);not null;uniqueIndex"` // given to the invitee to book or release the hold
+	StartTime        time.Time `gorm:"not null"`
+	EndTime          time.Time `gorm:"not null"`
+	ExpiresAt        time.Time `gorm:"not null;index"`
+	RequesterIP      string    `gorm:"type:varchar(45);index"` // limits how many slots one invitee can hold at once
+}
+
+// MeetingNote is an internal note an advisor keeps on a meeting, never shown to the invitee
+type MeetingNote struct {
+	gorm.Model
+	MeetingID uint   `gorm:"not null;index"`
+	UserID    uint   `gorm:"not null;index"`
+	Body      string `gorm:"type:text;not null"`
+}
+
+// Recurrence frequencies of a meeting series
+const (
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+// MeetingSeries groups the occurrences of a recurring booking, such as every other Tuesday at 10:00
+type MeetingSeries struct {
+	gorm.Model
+	SchedulingLinkID  uint      `gorm:"not null;index"`
+	UserID            uint      `gorm:"not null;index"`
+	ClientEmail       string    `gorm:"not null"`
+	Frequency         string    `gorm:"type:varchar(16);not null"` // weekly or monthly
+	Interval          int       `gorm:"not null;default:1"`        // every Interval weeks or months
+	Occurrences       int       `gorm:"not null"`
+	Meetings          []Meeting `gorm:"foreignKey:SeriesID"`
 }
\ No newline at end of file
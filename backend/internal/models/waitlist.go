@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WaitlistEntry is an invitee waiting for a slot of a link to open up, on a specific date or any
+// date. Entries are offered slots in the order they joined, and an offer holds the slot until the
+// invitee claims it or the offer runs out.
+type WaitlistEntry struct {
+	gorm.Model
+	SchedulingLinkID uint    `gorm:"not null;index"`
+	UserID           uint    `gorm:"not null;index"`
+	Email            string  `gorm:"not null"`
+	Date             *string `gorm:"type:varchar(10)"`                      // yyyy-mm-dd in the advisor's time zone; nil means any date
+	Token            string  `gorm:"type:varchar(64);not null;uniqueIndex"` // lets the invitee leave the waitlist
+	Status           string  `gorm:"type:varchar(16);not null;default:'waiting'"`
+	HoldToken        *string `gorm:"type:varchar(64);uniqueIndex"` // the slot hold of the current offer, used to claim it
+	OfferedStart     *time.Time
+	OfferedEnd       *time.Time
+	OfferExpiresAt   *time.Time
+}
+
+// Waitlist entry statuses
+const (
+	WaitlistStatusWaiting = "waiting"
+	WaitlistStatusOffered = "offered"
+	WaitlistStatusClaimed = "claimed"
+	WaitlistStatusExpired = "expired" // the offer ran out or the date passed
+)
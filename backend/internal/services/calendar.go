@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/advisor-scheduling/internal/models"
+	"github.com/yourusername/advisor-scheduling/internal/utils"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+	"gorm.io/gorm"
+)
+
+// BusyInterval is a span of time already taken on one of the advisor's calendars
+type BusyInterval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// BusySource describes a calendar that was consulted while collecting busy intervals
+type BusySource struct {
+	AccountEmail string `json:"account_email"`
+	CalendarID   string `json:"calendar_id"`
+	Status       string `json:"status"` // "ok" or "error"
+	Error        string `json:"error,omitempty"`
+}
+
+type CalendarService struct {
+	db *gorm.DB
+}
+
+func NewCalendarService(db *gorm.DB) *CalendarService {
+	return &CalendarService{
+		db: db,
+	}
+}
+
+// GetBusyIntervals collects busy time between start and end from every active Google account of a user.
+// A failing account or calendar is reported in the returned sources and skipped, so the remaining
+// calendars still contribute. An error is only returned if the accounts could not be loaded.
+func (s *CalendarService) GetBusyIntervals(ctx context.Context, userID uint, start, end time.Time) ([]BusyInterval, []BusySource, error) {
+	var accounts []models.GoogleAccount
+	if err := s.db.Where("user_id = ? AND is_active = ?", userID, true).Find(&accounts).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch google accounts: %v", err)
+	}
+
+	busy := []BusyInterval{}
+	sources := []BusySource{}
+
+	for _, account := range accounts {
+		// Get calendar IDs to check for this account
+		calendarIDs := account.CalendarIDs
+		if len(calendarIDs) == 0 {
+			calendarIDs = []string{"primary"} // Default to primary calendar if none specified
+		}
+
+		client := utils.GetGoogleClient(ctx, account.AccessToken)
+		srv, err := calendar.NewService(ctx, option.WithHTTPClient(client))
+		if err != nil {
+			for _, calendarID := range calendarIDs {
+				sources = append(sources, BusySource{
+					AccountEmail: account.Email,
+					CalendarID:   calendarID,
+					Status:       "error",
+					Error:        fmt.Sprintf("failed to create calendar service: %v", err),
+				})
+			}
+			continue
+		}
+
+		for _, calendarID := range calendarIDs {
+			intervals, err := listBusyIntervals(srv, calendarID, start, end)
+			if err != nil {
+				sources = append(sources, BusySource{
+					AccountEmail: account.Email,
+					CalendarID:   calendarID,
+					Status:       "error",
+					Error:        err.Error(),
+				})
+				continue
+			}
+
+			busy = append(busy, intervals...)
+			sources = append(sources, BusySource{
+				AccountEmail: account.Email,
+				CalendarID:   calendarID,
+				Status:       "ok",
+			})
+		}
+	}
+
+	return busy, sources, nil
+}
+
+// listBusyIntervals pages through the events of one calendar and keeps the ones that block time
+func listBusyIntervals(srv *calendar.Service, calendarID string, start, end time.Time) ([]BusyInterval, error) {
+	var intervals []BusyInterval
+	pageToken := ""
+
+	for {
+		call := srv.Events.List(calendarID).
+			TimeMin(start.Format(time.RFC3339)).
+			TimeMax(end.Format(time.RFC3339)).
+			SingleEvents(true).
+			OrderBy("startTime").
+			MaxResults(250)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		events, err := call.Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch events: %v", err)
+		}
+
+		for _, event := range events.Items {
+			if !blocksTime(event) {
+				continue
+			}
+
+			eventStart, eventEnd, err := eventBounds(event, events.TimeZone)
+			if err != nil {
+				continue
+			}
+			intervals = append(intervals, BusyInterval{Start: eventStart, End: eventEnd})
+		}
+
+		if events.NextPageToken == "" {
+			break
+		}
+		pageToken = events.NextPageToken
+	}
+
+	return intervals, nil
+}
+
+// blocksTime reports whether an event makes the advisor unavailable
+func blocksTime(event *calendar.Event) bool {
+	if event.Status == "cancelled" || event.Transparency == "transparent" {
+		return false
+	}
+
+	// Skip invitations the advisor has declined
+	for _, attendee := range event.Attendees {
+		if attendee.Self && attendee.ResponseStatus == "declined" {
+			return false
+		}
+	}
+
+	return event.Start != nil && event.End != nil
+}
+
+// eventBounds returns the start and end of an event, handling both timed and all-day events
+func eventBounds(event *calendar.Event, calendarTimeZone string) (time.Time, time.Time, error) {
+	if event.Start.DateTime != "" && event.End.DateTime != "" {
+		start, err := time.Parse(time.RFC3339, event.Start.DateTime)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		end, err := time.Parse(time.RFC3339, event.End.DateTime)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return start, end, nil
+	}
+
+	// All-day events only carry a date, which is relative to the calendar's time zone
+	loc := time.UTC
+	if calendarTimeZone != "" {
+		if l, err := time.LoadLocation(calendarTimeZone); err == nil {
+			loc = l
+		}
+	}
+	start, err := time.ParseInLocation("2006-01-02", event.Start.Date, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := time.ParseInLocation("2006-01-02", event.End.Date, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, end, nil
+}
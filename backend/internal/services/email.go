@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -46,6 +47,13 @@ func (s *EmailService) SendMeetingNotification(ctx context.Context, toEmail stri
 	to := mail.NewEmail("", toEmail)
 	subject := "New Meeting Scheduled"
 
+	// Links that require approval send requests the advisor still has to accept
+	approval := ""
+	if deadline, ok := meetingDetails["approval_deadline"].(string); ok && deadline != "" {
+		subject = "New Meeting Request"
+		approval = "Accept or decline by: " + deadline + "\n"
+	}
+
 	// Try to find the contact in HubSpot first
 	contact, err := s.hubspot.FindContactByEmail(meetingDetails["client_email"].(string))
 	if err != nil {
@@ -54,6 +62,7 @@ func (s *EmailService) SendMeetingNotification(ctx context.Context, toEmail stri
 
 	// Only scrape LinkedIn if we don't have enough context from HubSpot
 	var linkedinProfile *LinkedInProfile
+	scraped := false
 	hasEnoughContext := false
 	if contact != nil && len(contact.Notes) > 0 {
 		// Check if we have any recent or relevant notes
@@ -89,25 +98,42 @@ func (s *EmailService) SendMeetingNotification(ctx context.Context, toEmail stri
 				}
 			} else {
 				linkedinProfile = profile
+				scraped = true
 			}
 		}
 	}
 
+	// Keep what was found about the client for their next meetings
+	if clientID, ok := meetingDetails["client_id"].(uint); ok {
+		meetingID, _ := meetingDetails["meeting_id"].(uint)
+		s.saveClientEnrichment(clientID, meetingID, contact, scraped, linkedinProfile)
+	}
+
+	// Recurring series describe their occurrences after the first meeting's times
+	recurrence := ""
+	if summary, ok := meetingDetails["recurrence"].(string); ok && summary != "" {
+		recurrence = "Recurs: " + summary + "\n"
+	}
+
 	// Format the meeting details into a readable message
 	content := fmt.Sprintf(`
-New Meeting Scheduled
+%s
 
 Client Email: %s
-LinkedIn URL: %s
+%sLinkedIn URL: %s
 Start Time: %s
 End Time: %s
-
+%s%s
 Questions and Answers:
 `, 
+		subject,
 		meetingDetails["client_email"],
+		participantLines(meetingDetails),
 		meetingDetails["linkedin_url"],
 		meetingDetails["start_time"],
-		meetingDetails["end_time"])
+		meetingDetails["end_time"],
+		recurrence,
+		approval)
 
 	// Process and enrich answers
 	answers := meetingDetails["answers"].(models.StringSlice)
@@ -162,4 +188,239 @@ Questions and Answers:
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
+
+// saveClientEnrichment stores the HubSpot contact and the scraped LinkedIn profile found for a
+// client, so they are kept across the client's meetings
+func (s *EmailService) saveClientEnrichment(clientID uint, meetingID uint, contact *HubSpotContact, scraped bool, profile *LinkedInProfile) {
+	updates := map[string]interface{}{}
+	if contact != nil && contact.ID != "" {
+		updates["hubspot_contact_id"] = contact.ID
+		if err := s.db.Model(&models.Meeting{}).Where("id = ?", meetingID).Update("hubspot_contact_id", contact.ID).Error; err != nil {
+			fmt.Printf("Failed to update meeting HubSpot contact: %v\n", err)
+		}
+	}
+	if scraped && profile != nil {
+		data, err := json.Marshal(profile)
+		if err != nil {
+			fmt.Printf("Failed to marshal LinkedIn profile: %v\n", err)
+		} else {
+			updates["linkedin_data"] = string(data)
+		}
+	}
+	if len(updates) == 0 {
+		return
+	}
+
+	if err := s.db.Model(&models.Client{}).Where("id = ?", clientID).Updates(updates).Error; err != nil {
+		fmt.Printf("Failed to update client enrichment: %v\n", err)
+	}
+}
+
+// participantLines lists the invitee's name and phone and their guests, leaving out what the
+// invitee didn't give
+func participantLines(meetingDetails map[string]interface{}) string {
+	lines := ""
+	if name, ok := meetingDetails["client_name"].(string); ok && name != "" {
+		lines += "Client Name: " + name + "\n"
+	}
+	if phone, ok := meetingDetails["client_phone"].(string); ok && phone != "" {
+		lines += "Client Phone: " + phone + "\n"
+	}
+	if guests, ok := meetingDetails["guest_emails"].(string); ok && guests != "" {
+		lines += "Guests: " + guests + "\n"
+	}
+	return lines
+}
+
+// SendMeetingChangeNotification tells the advisor that an invitee cancelled or rescheduled a meeting
+func (s *EmailService) SendMeetingChangeNotification(ctx context.Context, toEmail string, change string, meetingDetails map[string]interface{}) error {
+	to := mail.NewEmail("", toEmail)
+	title := strings.ToUpper(change[:1]) + change[1:]
+	subject := "Meeting " + title
+
+	content := fmt.Sprintf(`
+Meeting %s by the Invitee
+
+Client Email: %s
+%s`,
+		title,
+		meetingDetails["client_email"],
+		participantLines(meetingDetails))
+
+	if previousStart, ok := meetingDetails["previous_start_time"].(string); ok {
+		content += fmt.Sprintf("Previous Start Time: %s\nPrevious End Time: %s\n", previousStart, meetingDetails["previous_end_time"])
+	}
+	content += fmt.Sprintf("Start Time: %s\nEnd Time: %s\n", meetingDetails["start_time"], meetingDetails["end_time"])
+	if reason, ok := meetingDetails["reason"].(string); ok && reason != "" {
+		content += fmt.Sprintf("\nReason: %s\n", reason)
+	}
+
+	// Create the email message
+	message := mail.NewSingleEmail(s.from, subject, to, content, content)
+
+	// Send the email
+	response, err := s.client.Send(message)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+
+	if response.StatusCode >= 400 {
+		return fmt.Errorf("sendgrid API error: %s", response.Body)
+	}
+
+	return nil
+}
+
+// SendBookingRequestOutcome tells an invitee that the advisor accepted or declined their booking
+// request, or that it expired before the advisor acted on it
+func (s *EmailService) SendBookingRequestOutcome(ctx context.Context, toEmail string, outcome string, meetingDetails map[string]interface{}) error {
+	to := mail.NewEmail("", toEmail)
+
+	request := "meeting"
+	if series, ok := meetingDetails["series"].(bool); ok && series {
+		request = "recurring meeting"
+	}
+	var subject, summary string
+	switch outcome {
+	case "accepted":
+		subject = "Meeting Request Accepted"
+		summary = fmt.Sprintf("Your %s request has been accepted and is now confirmed.", request)
+	case "declined":
+		subject = "Meeting Request Declined"
+		summary = fmt.Sprintf("Your %s request has been declined.", request)
+	default:
+		subject = "Meeting Request Expired"
+		summary = fmt.Sprintf("Your %s request expired before it could be confirmed. Please book another time.", request)
+	}
+
+	content := fmt.Sprintf(`
+%s
+
+%s
+
+Meeting: %s
+Start Time: %s
+End Time: %s
+`,
+		subject,
+		summary,
+		meetingDetails["title"],
+		meetingDetails["start_time"],
+		meetingDetails["end_time"])
+	if reason, ok := meetingDetails["reason"].(string); ok && reason != "" {
+		content += fmt.Sprintf("\nReason: %s\n", reason)
+	}
+
+	// Create the email message
+	message := mail.NewSingleEmail(s.from, subject, to, content, content)
+
+	// Send the email
+	response, err := s.client.Send(message)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+
+	if response.StatusCode >= 400 {
+		return fmt.Errorf("sendgrid API error: %s", response.Body)
+	}
+
+	return nil
+}
+
+// SendBookingConfirmation confirms a new booking to the invitee or one of their guests, with the
+// meeting attached as a calendar file
+func (s *EmailService) SendBookingConfirmation(ctx context.Context, toEmail string, meetingDetails map[string]interface{}, ics string) error {
+	to := mail.NewEmail("", toEmail)
+	subject := "Meeting Confirmed"
+	summary := "Your meeting has been booked."
+	// Links that require approval only confirm once the advisor accepts
+	if deadline, ok := meetingDetails["approval_deadline"].(string); ok && deadline != "" {
+		subject = "Meeting Requested"
+		summary = "Your meeting has been requested. You'll hear back once the advisor accepts or declines it."
+	}
+
+	content := fmt.Sprintf(`
+%s
+
+%s
+
+Meeting: %s
+Advisor: %s
+Start Time: %s
+End Time: %s
+`,
+		subject,
+		summary,
+		meetingDetails["title"],
+		meetingDetails["advisor_name"],
+		meetingDetails["start_time"],
+		meetingDetails["end_time"])
+	if recurrence, ok := meetingDetails["recurrence"].(string); ok && recurrence != "" {
+		content += fmt.Sprintf("Repeats: %s\n", recurrence)
+	}
+	if guests, ok := meetingDetails["guest_emails"].(string); ok && guests != "" {
+		content += fmt.Sprintf("Guests: %s\n", guests)
+	}
+
+	// Create the email message, with the meeting to add to a calendar
+	message := mail.NewSingleEmail(s.from, subject, to, content, content)
+	attachment := mail.NewAttachment()
+	attachment.SetContent(base64.StdEncoding.EncodeToString([]byte(ics)))
+	attachment.SetType("text/calendar")
+	attachment.SetFilename("meeting.ics")
+	attachment.SetDisposition("attachment")
+	message.AddAttachment(attachment)
+
+	// Send the email
+	response, err := s.client.Send(message)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+
+	if response.StatusCode >= 400 {
+		return fmt.Errorf("sendgrid API error: %s", response.Body)
+	}
+
+	return nil
+}
+
+// SendWaitlistOffer offers a waitlisted invitee a slot that opened up, with the link to claim it
+// before the offer runs out
+func (s *EmailService) SendWaitlistOffer(ctx context.Context, toEmail string, offerDetails map[string]interface{}) error {
+	to := mail.NewEmail("", toEmail)
+	subject := "A Time Slot Opened Up"
+
+	content := fmt.Sprintf(`
+A Time Slot Opened Up
+
+A slot you were waiting for is now available and is being held for you.
+
+Meeting: %s
+Start Time: %s
+End Time: %s
+
+Claim it before %s:
+%s
+`,
+		offerDetails["title"],
+		offerDetails["start_time"],
+		offerDetails["end_time"],
+		offerDetails["expires_at"],
+		offerDetails["claim_url"])
+
+	// Create the email message
+	message := mail.NewSingleEmail(s.from, subject, to, content, content)
+
+	// Send the email
+	response, err := s.client.Send(message)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+
+	if response.StatusCode >= 400 {
+		return fmt.Errorf("sendgrid API error: %s", response.Body)
+	}
+
+	return nil
+}
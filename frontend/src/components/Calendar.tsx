@@ -17,6 +17,7 @@ import client from '../api/client';
 interface TimeSlot {
 	start: Date;
 	end: Date;
+	recommended?: boolean; // set when the link ranks slots to keep the advisor's day compact
 }
 
 interface CalendarProps {
@@ -39,11 +40,13 @@ export default function Calendar({ onSlotSelect, selectedSlot, linkId, duration
 			const response = await client.get(`/scheduling/links/${linkId}/slots/public`, {
 				params: {
 					date: format(date, 'yyyy-MM-dd'),
+					tz: Intl.DateTimeFormat().resolvedOptions().timeZone,
 				},
 			});
-			const slots = response.data.map((slot: any) => ({
+			const slots = response.data.slots.map((slot: any) => ({
 				start: new Date(slot.start),
 				end: new Date(slot.end),
+				recommended: slot.recommended,
 			}));
 			setAvailableSlots(slots);
 		} catch (err: any) {
@@ -117,6 +120,8 @@ export default function Calendar({ onSlotSelect, selectedSlot, linkId, duration
 												<Button
 													key={index}
 													variant={isSlotSelected(slot) ? 'contained' : 'outlined'}
+													color={slot.recommended ? 'success' : 'primary'}
+													title={slot.recommended ? 'Recommended time' : undefined}
 													onClick={() => handleTimeSelect(slot)}
 													sx={{ minWidth: '120px' }}
 												>
@@ -28,6 +28,13 @@ interface SchedulingWindow {
   is_active: boolean;
 }
 
+interface Holiday {
+  id: number;
+  start_date: string;
+  end_date: string;
+  name: string;
+}
+
 const weekdays = [
   'Sunday',
   'Monday',
@@ -40,6 +47,7 @@ const weekdays = [
 
 export default function SchedulingWindows() {
   const [windows, setWindows] = useState<SchedulingWindow[]>([]);
+  const [holidays, setHolidays] = useState<Holiday[]>([]);
   const [open, setOpen] = useState(false);
   const [formData, setFormData] = useState({
     start_hour: 9,
@@ -51,7 +59,8 @@ export default function SchedulingWindows() {
   const fetchWindows = async () => {
     try {
       const response = await client.get('/api/scheduling/windows');
-      setWindows(response.data);
+      setWindows(response.data.windows);
+      setHolidays(response.data.holidays);
     } catch (error) {
       console.error('Failed to fetch scheduling windows:', error);
       setError('Failed to load scheduling windows');
@@ -152,6 +161,19 @@ export default function SchedulingWindows() {
         ))}
       </Box>
 
+      {holidays.length > 0 && (
+        <Box sx={{ mt: 4 }}>
+          <Typography variant="h6" component="h2" sx={{ mb: 1 }}>Upcoming Holidays</Typography>
+          {holidays.map((holiday) => (
+            <Typography key={String(holiday.id)} variant="body2" color="text.secondary">
+              {holiday.start_date === holiday.end_date
+                ? holiday.start_date
+                : `${holiday.start_date} - ${holiday.end_date}`}: {holiday.name}
+            </Typography>
+          ))}
+        </Box>
+      )}
+
       <Dialog open={open} onClose={handleClose}>
         <DialogTitle>Add Scheduling Window</DialogTitle>
         <DialogContent>
@@ -21,6 +21,9 @@ import {
 	CircularProgress,
 	Collapse,
 	Divider,
+	Chip,
+	FormControlLabel,
+	Switch,
 } from '@mui/material';
 import AddIcon from '@mui/icons-material/Add';
 import DeleteIcon from '@mui/icons-material/Delete';
@@ -38,13 +41,42 @@ import CalendarEvents from '../components/CalendarEvents';
 interface Meeting {
 	id: number;
 	client_email: string;
+	client_name?: string;
+	client_phone?: string;
+	guest_emails?: string[];
 	linkedin_url: string;
 	start_time: string;
 	end_time: string;
 	answers: string[];
 	context_notes: string;
+	status: MeetingStatus;
 }
 
+type MeetingStatus = 'pending' | 'scheduled' | 'completed' | 'no_show' | 'cancelled' | 'declined' | 'expired';
+
+// Meetings that hold their slot; the others were cancelled, declined or expired
+const liveMeetingStatuses: MeetingStatus[] = ['pending', 'scheduled', 'completed', 'no_show'];
+
+const meetingStatusLabels: { [status in MeetingStatus]: string } = {
+	pending: 'Pending',
+	scheduled: 'Scheduled',
+	completed: 'Completed',
+	no_show: 'No-show',
+	cancelled: 'Cancelled',
+	declined: 'Declined',
+	expired: 'Expired',
+};
+
+const meetingStatusColors: { [status in MeetingStatus]: 'default' | 'primary' | 'success' | 'warning' | 'error' } = {
+	pending: 'warning',
+	scheduled: 'primary',
+	completed: 'success',
+	no_show: 'error',
+	cancelled: 'default',
+	declined: 'default',
+	expired: 'default',
+};
+
 interface SchedulingLink {
 	id: string;
 	title: string;
@@ -73,6 +105,12 @@ export default function Dashboard() {
 	});
 	const [newQuestion, setNewQuestion] = useState('');
 	const [expandedLinks, setExpandedLinks] = useState<{ [key: string]: boolean }>({});
+	const [showReleasedMeetings, setShowReleasedMeetings] = useState(false);
+
+	const visibleMeetings = (link: SchedulingLink) =>
+		(link.meetings || []).filter(
+			(meeting) => showReleasedMeetings || liveMeetingStatuses.includes(meeting.status)
+		);
 
 	useEffect(() => {
 		const fetchData = async () => {
@@ -205,13 +243,24 @@ export default function Dashboard() {
 						<Typography variant="h4" component="h1">
 							Scheduling Links
 						</Typography>
-						<Button
-							variant="contained"
-							startIcon={<AddIcon />}
-							onClick={handleClickOpen}
-						>
-							Create Link
-						</Button>
+						<Box sx={{ display: 'flex', alignItems: 'center', gap: 2 }}>
+							<FormControlLabel
+								control={
+									<Switch
+										checked={showReleasedMeetings}
+										onChange={(e) => setShowReleasedMeetings(e.target.checked)}
+									/>
+								}
+								label="Show cancelled meetings"
+							/>
+							<Button
+								variant="contained"
+								startIcon={<AddIcon />}
+								onClick={handleClickOpen}
+							>
+								Create Link
+							</Button>
+						</Box>
 					</Box>
 
 					<Grid container spacing={2}>
@@ -260,17 +309,27 @@ export default function Dashboard() {
 											<Box sx={{ mt: 2 }}>
 												<Divider sx={{ my: 2 }} />
 												<Typography variant="subtitle1" gutterBottom>
-													Meetings ({link.meetings?.length || 0})
+													Meetings ({visibleMeetings(link).length})
 												</Typography>
-												{link.meetings && link.meetings.length > 0 ? (
+												{visibleMeetings(link).length > 0 ? (
 													<List>
-														{link.meetings.map((meeting) => (
-															<ListItem key={meeting.id} divider>
+														{visibleMeetings(link).map((meeting) => (
+															<ListItem
+																key={meeting.id}
+																divider
+																sx={{ opacity: liveMeetingStatuses.includes(meeting.status) ? 1 : 0.6 }}
+															>
 																<ListItemText
 																	primary={
 																		<Box component="span">
-																			<Box component="span" sx={{ display: 'block', typography: 'subtitle2' }}>
+																			<Box component="span" sx={{ display: 'flex', alignItems: 'center', gap: 1, typography: 'subtitle2' }}>
 																				{meeting.client_email}
+																				<Chip
+																					component="span"
+																					size="small"
+																					label={meetingStatusLabels[meeting.status] || meeting.status}
+																					color={meetingStatusColors[meeting.status] || 'default'}
+																				/>
 																			</Box>
 																			<Box component="span" sx={{ display: 'block', typography: 'body2', color: 'text.secondary' }}>
 																				{format(new Date(meeting.start_time), 'MMM d, yyyy h:mm a')}
@@ -279,6 +338,16 @@ export default function Dashboard() {
 																	}
 																	secondary={
 																		<Box component="span" sx={{ mt: 1 }}>
+																			{(meeting.client_name || meeting.client_phone) && (
+																				<Box component="span" sx={{ display: 'block', typography: 'body2', color: 'text.secondary' }}>
+																					{[meeting.client_name, meeting.client_phone].filter(Boolean).join(' · ')}
+																				</Box>
+																			)}
+																			{!!meeting.guest_emails?.length && (
+																				<Box component="span" sx={{ display: 'block', typography: 'body2', color: 'text.secondary' }}>
+																					Guests: {meeting.guest_emails.join(', ')}
+																				</Box>
+																			)}
 																			{meeting.linkedin_url && (
 																				<Box component="span" sx={{ display: 'block', typography: 'body2', color: 'text.secondary' }}>
 																					LinkedIn: {meeting.linkedin_url}
@@ -1,5 +1,5 @@
 import { useEffect, useState } from 'react';
-import { useParams } from 'react-router-dom';
+import { useParams, useSearchParams } from 'react-router-dom';
 import {
 	Box,
 	Button,
@@ -29,6 +29,8 @@ interface SchedulingLink {
 	expires_at?: string;
 	max_days_in_advance: number;
 	custom_questions: string[];
+	requires_approval?: boolean;
+	max_guests?: number;
 }
 
 interface TimeSlot {
@@ -38,6 +40,9 @@ interface TimeSlot {
 
 interface FormData {
 	email: string;
+	name: string;
+	phone: string;
+	guests: string; // comma-separated guest emails
 	linkedin_url: string;
 	answers: { [key: string]: string };
 }
@@ -59,18 +64,28 @@ const formatUTCTime = (date: Date) => {
 
 export default function Scheduling() {
 	const { id } = useParams<{ id: string }>();
+	const [searchParams] = useSearchParams();
 	const [activeStep, setActiveStep] = useState(0);
 	const [link, setLink] = useState<SchedulingLink | null>(null);
 	const [loading, setLoading] = useState(true);
 	const [error, setError] = useState<string | null>(null);
 	const [selectedSlot, setSelectedSlot] = useState<TimeSlot | null>(null);
+	const [holdToken, setHoldToken] = useState<string | null>(null);
 	const [formData, setFormData] = useState<FormData>({
 		email: '',
+		name: '',
+		phone: '',
+		guests: '',
 		linkedin_url: '',
 		answers: {},
 	});
 	const [submitting, setSubmitting] = useState(false);
 	const [success, setSuccess] = useState(false);
+	const [pending, setPending] = useState(false);
+	const [manageToken, setManageToken] = useState<string | null>(null);
+	const [waitlistEmail, setWaitlistEmail] = useState('');
+	const [waitlistDate, setWaitlistDate] = useState('');
+	const [waitlistMessage, setWaitlistMessage] = useState<string | null>(null);
 
 	useEffect(() => {
 		const fetchLink = async () => {
@@ -83,8 +98,22 @@ export default function Scheduling() {
 					return acc;
 				}, {});
 				setFormData(prev => ({ ...prev, answers: initialAnswers }));
-			} catch (err) {
-				setError('Failed to load scheduling link');
+
+				// A claim link from the waitlist goes straight to the details of the offered slot
+				const claim = searchParams.get('claim');
+				if (claim) {
+					try {
+						const offer = await client.get(`/scheduling/waitlist/claim/${claim}`);
+						setSelectedSlot({ start: new Date(offer.data.offered_start), end: new Date(offer.data.offered_end) });
+						setHoldToken(offer.data.hold_token);
+						setFormData(prev => ({ ...prev, email: offer.data.email }));
+						setActiveStep(1);
+					} catch (err) {
+						console.error('Error fetching waitlist offer:', err);
+					}
+				}
+			} catch (err: any) {
+				setError(err.response?.data?.error || 'Failed to load scheduling link');
 				console.error('Error fetching scheduling link:', err);
 			} finally {
 				setLoading(false);
@@ -92,10 +121,38 @@ export default function Scheduling() {
 		};
 
 		fetchLink();
-	}, [id]);
+	}, [id, searchParams]);
 
-	const handleSlotSelect = (slot: TimeSlot) => {
+	const handleSlotSelect = async (slot: TimeSlot) => {
 		setSelectedSlot(slot);
+		setError(null);
+
+		// Hold the slot while the invitee fills in the form, releasing any earlier hold
+		try {
+			const response = await client.post(`/scheduling/links/${id}/holds/public`, {
+				start_time: slot.start,
+				replace_hold_token: holdToken,
+			});
+			setHoldToken(response.data.hold_token);
+		} catch (err: any) {
+			setSelectedSlot(null);
+			setHoldToken(null);
+			setError(err.response?.data?.error || 'This time slot is no longer available. Please choose another.');
+			console.error('Error holding time slot:', err);
+		}
+	};
+
+	const handleJoinWaitlist = async () => {
+		try {
+			const response = await client.post(`/scheduling/links/${id}/waitlist/public`, {
+				email: waitlistEmail,
+				date: waitlistDate || undefined,
+			});
+			setWaitlistMessage(`You're number ${response.data.position} on the waitlist. We'll email you if a slot opens up.`);
+		} catch (err: any) {
+			console.error('Error joining waitlist:', err);
+			setWaitlistMessage(err.response?.data?.error || 'Failed to join the waitlist. Please try again.');
+		}
 	};
 
 	const handleInputChange = (field: string, value: string) => {
@@ -123,6 +180,9 @@ export default function Scheduling() {
 		return Object.values(formData.answers).every(answer => answer.trim() !== '');
 	};
 
+	const guestEmails = () =>
+		formData.guests.split(',').map(email => email.trim()).filter(email => email !== '');
+
 	const handleFinish = async () => {
 		if (!selectedSlot || !link) return;
 
@@ -130,14 +190,20 @@ export default function Scheduling() {
 		setError(null);
 
 		try {
-			await client.post(`/scheduling/links/${id}/meetings/public`, {
+			const response = await client.post(`/scheduling/links/${id}/meetings/public`, {
 				client_email: formData.email,
+				client_name: formData.name,
+				client_phone: formData.phone,
+				guest_emails: guestEmails(),
 				linkedin_url: formData.linkedin_url,
 				start_time: selectedSlot.start,
 				end_time: selectedSlot.end,
 				answers: formData.answers,
+				hold_token: holdToken,
 			});
 
+			setPending(response.data.status === 'pending');
+			setManageToken(response.data.manage_token);
 			setSuccess(true);
 		} catch (err: any) {
 			console.error('Failed to create meeting:', err);
@@ -162,6 +228,32 @@ export default function Scheduling() {
 			<Container maxWidth="md">
 				<Box sx={{ my: 4, textAlign: 'center' }}>
 					<Typography color="error">{error || 'Scheduling link not found'}</Typography>
+					{!link && (
+						<Stack spacing={2} sx={{ maxWidth: 400, mx: 'auto', mt: 4 }}>
+							<Typography variant="h6">Join the Waitlist</Typography>
+							<TextField
+								label="Email"
+								type="email"
+								value={waitlistEmail}
+								onChange={(e) => setWaitlistEmail(e.target.value)}
+								fullWidth
+							/>
+							<TextField
+								label="Date (optional)"
+								type="date"
+								value={waitlistDate}
+								onChange={(e) => setWaitlistDate(e.target.value)}
+								InputLabelProps={{ shrink: true }}
+								fullWidth
+							/>
+							<Button variant="contained" onClick={handleJoinWaitlist} disabled={!waitlistEmail}>
+								Join Waitlist
+							</Button>
+							{waitlistMessage && (
+								<Typography color="text.secondary">{waitlistMessage}</Typography>
+							)}
+						</Stack>
+					)}
 				</Box>
 			</Container>
 		);
@@ -173,11 +265,21 @@ export default function Scheduling() {
 				<Box sx={{ my: 4, textAlign: 'center' }}>
 					<CheckCircleIcon sx={{ fontSize: 60, color: 'success.main', mb: 2 }} />
 					<Typography variant="h5" gutterBottom>
-						Meeting Scheduled Successfully!
+						{pending ? 'Meeting Requested!' : 'Meeting Scheduled Successfully!'}
 					</Typography>
 					<Typography color="text.secondary" paragraph>
-						You will receive a confirmation email shortly.
+						{pending
+							? 'Your time is held while your request is reviewed. You will receive an email once it is accepted or declined.'
+							: 'You will receive a confirmation email shortly.'}
 					</Typography>
+					{manageToken && (
+						<Button
+							variant="outlined"
+							href={`${client.defaults.baseURL}/scheduling/meetings/manage/${manageToken}/ics`}
+						>
+							Add to Calendar
+						</Button>
+					)}
 				</Box>
 			</Container>
 		);
@@ -239,6 +341,28 @@ export default function Scheduling() {
 										required
 										fullWidth
 									/>
+									<TextField
+										label="Name"
+										value={formData.name}
+										onChange={(e) => handleInputChange('name', e.target.value)}
+										fullWidth
+									/>
+									<TextField
+										label="Phone"
+										type="tel"
+										value={formData.phone}
+										onChange={(e) => handleInputChange('phone', e.target.value)}
+										fullWidth
+									/>
+									{!!link.max_guests && (
+										<TextField
+											label="Guest Emails"
+											value={formData.guests}
+											onChange={(e) => handleInputChange('guests', e.target.value)}
+											fullWidth
+											helperText={`Separate emails with commas, up to ${link.max_guests} guests`}
+										/>
+									)}
 									<TextField
 										label="LinkedIn URL"
 										value={formData.linkedin_url}
@@ -270,7 +394,9 @@ export default function Scheduling() {
 									Confirmation
 								</Typography>
 								<Typography color="text.secondary" paragraph>
-									Review your booking details
+									{link.requires_approval
+										? 'Review your booking details. The advisor will confirm your request.'
+										: 'Review your booking details'}
 								</Typography>
 								<Stack spacing={3} sx={{ maxWidth: 600, mx: 'auto', mt: 3 }}>
 									<Paper sx={{ p: 3 }}>
@@ -295,6 +421,21 @@ export default function Scheduling() {
 										<Typography>
 											Email: {formData.email}
 										</Typography>
+										{formData.name && (
+											<Typography>
+												Name: {formData.name}
+											</Typography>
+										)}
+										{formData.phone && (
+											<Typography>
+												Phone: {formData.phone}
+											</Typography>
+										)}
+										{guestEmails().length > 0 && (
+											<Typography>
+												Guests: {guestEmails().join(', ')}
+											</Typography>
+										)}
 										<Typography>
 											LinkedIn: {formData.linkedin_url}
 										</Typography>